@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	bucketExtension   = "extension"
+	bucketDate        = "date"
+	bucketChunk       = "chunk"
+	defaultBucketSize = 1000
+)
+
+var bucketModes = []string{"", bucketExtension, bucketDate, bucketChunk}
+
+type chunkState struct {
+	bucket int
+	count  int
+}
+
+func (c Config) bucketSize() int {
+	if c.BucketSize <= 0 {
+		return defaultBucketSize
+	}
+	return c.BucketSize
+}
+func nextBucketMode(mode string) string {
+	for i, m := range bucketModes {
+		if m == mode {
+			return bucketModes[(i+1)%len(bucketModes)]
+		}
+	}
+	return ""
+}
+func getBucketDisplay(c Config) string {
+	switch c.BucketBy {
+	case bucketExtension:
+		return "by extension (jpg/, pdf/)"
+	case bucketDate:
+		return "by date (2024/2024-05/)"
+	case bucketChunk:
+		return fmt.Sprintf("%d files per folder (0001/, 0002/)", c.bucketSize())
+	}
+	return "none"
+}
+func lastChunk(dest string) chunkState {
+	var state chunkState
+	entries, _ := os.ReadDir(dest)
+	for _, entry := range entries {
+		if n, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() && n > state.bucket {
+			state.bucket = n
+		}
+	}
+	if state.bucket == 0 {
+		state.bucket = 1
+		return state
+	}
+	files, _ := os.ReadDir(filepath.Join(dest, fmt.Sprintf("%04d", state.bucket)))
+	state.count = len(files)
+	return state
+}
+func (m model) bucketDir(file sourceFile, dest, fileName string) (string, error) {
+	switch m.config.BucketBy {
+	case bucketExtension:
+		_, ext := splitExtension(filepath.Base(fileName))
+		if ext == "" {
+			return "other", nil
+		}
+		return strings.ToLower(strings.TrimPrefix(ext, ".")), nil
+	case bucketDate:
+		info, err := file.stat()
+		if err != nil {
+			return "", err
+		}
+		date := fileDate(file.path, info, m.config.UseExifDate)
+		return filepath.Join(date.Format("2006"), date.Format("2006-01")), nil
+	case bucketChunk:
+		state, ok := m.chunks[dest]
+		if !ok {
+			fresh := lastChunk(dest)
+			state = &fresh
+			if m.chunks != nil {
+				m.chunks[dest] = state
+			}
+		}
+		if state.count >= m.config.bucketSize() {
+			state.bucket++
+			state.count = 0
+		}
+		state.count++
+		return fmt.Sprintf("%04d", state.bucket), nil
+	}
+	return "", nil
+}
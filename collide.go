@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+var caseInsensitiveFilesystems = map[string]bool{
+	"vfat": true, "exfat": true, "ntfs": true, "smb": true, "smbfs": true, "windows": true, "apfs": true, "hfs": true,
+}
+
+type plannedDir struct {
+	fold  bool
+	names map[string]bool
+}
+
+func nameKey(name string, fold bool) string {
+	name = norm.NFC.String(name)
+	if fold {
+		name = cases.Fold().String(name)
+	}
+	return name
+}
+func (m model) plannedDir(dir string) *plannedDir {
+	if m.planned == nil {
+		return nil
+	}
+	if planned, ok := m.planned[dir]; ok {
+		return planned
+	}
+	planned := &plannedDir{fold: caseInsensitiveFilesystems[filesystemType(dir)], names: map[string]bool{}}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		planned.names[nameKey(entry.Name(), planned.fold)] = true
+	}
+	m.planned[dir] = planned
+	return planned
+}
+func (m model) nameTaken(path string) bool {
+	if !pathFree(path) {
+		return true
+	}
+	planned := m.plannedDir(filepath.Dir(path))
+	return planned != nil && planned.names[nameKey(filepath.Base(path), planned.fold)]
+}
+func (m model) reserveName(path string) {
+	if planned := m.plannedDir(filepath.Dir(path)); planned != nil {
+		planned.names[nameKey(filepath.Base(path), planned.fold)] = true
+	}
+}
+func (m model) releaseName(path string) {
+	if planned := m.plannedDir(filepath.Dir(path)); planned != nil {
+		delete(planned.names, nameKey(filepath.Base(path), planned.fold))
+	}
+}
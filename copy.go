@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type copyResult struct {
+	dest        string
+	path        string
+	hash        string
+	skipped     bool
+	err         error
+	sidecars    []sidecarCopy
+	sidecarErr  error
+	renamedFrom string
+}
+type jobStats struct {
+	total         int
+	copied        int
+	skipped       int
+	sidecars      int
+	sidecarFailed int
+	renames       []string
+	failures      []string
+	notCopied     []string
+	byRoot        map[string]int
+	byDest        map[string]int
+	failedByDest  map[string]int
+	manifestErr   error
+}
+
+func newJobStats(total int) jobStats {
+	return jobStats{
+		total:        total,
+		byRoot:       map[string]int{},
+		byDest:       map[string]int{},
+		failedByDest: map[string]int{},
+	}
+}
+func (s *jobStats) record(file sourceFile, results []copyResult) {
+	ok := len(results) > 0
+	skipped := true
+	for _, result := range results {
+		if result.skipped {
+			continue
+		}
+		skipped = false
+		s.sidecars += len(result.sidecars)
+		if result.sidecarErr != nil {
+			s.sidecarFailed++
+		}
+		if result.renamedFrom != "" && result.err == nil {
+			s.renames = append(s.renames, fmt.Sprintf("%s → %s", result.renamedFrom, filepath.Base(result.path)))
+		}
+		if result.err != nil {
+			s.failures = append(s.failures, fmt.Sprintf("%s → %s: %v", file.rel, result.dest, result.err))
+			s.failedByDest[result.dest]++
+			ok = false
+		} else {
+			s.byDest[result.dest]++
+		}
+	}
+	if ok && skipped {
+		s.skipped++
+	} else if ok {
+		s.copied++
+		s.byRoot[file.root]++
+	}
+}
+func copyError(results []copyResult) error {
+	var errs []error
+	for _, result := range results {
+		if result.err != nil {
+			errs = append(errs, result.err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type fanoutWriter struct {
+	writers []*io.PipeWriter
+	errs    []error
+}
+
+func (f *fanoutWriter) Write(p []byte) (int, error) {
+	alive := false
+	for i, w := range f.writers {
+		if f.errs[i] != nil {
+			continue
+		}
+		if _, err := w.Write(p); err != nil {
+			f.errs[i] = err
+			continue
+		}
+		alive = true
+	}
+	if !alive {
+		return 0, errors.Join(f.errs...)
+	}
+	return len(p), nil
+}
+func writeToAll(src io.Reader, dests []*os.File) []error {
+	errs := make([]error, len(dests))
+	if len(dests) == 1 {
+		_, errs[0] = io.Copy(dests[0], src)
+		return errs
+	}
+	fanout := &fanoutWriter{errs: make([]error, len(dests))}
+	var wg sync.WaitGroup
+	for i, dest := range dests {
+		r, w := io.Pipe()
+		fanout.writers = append(fanout.writers, w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := io.Copy(dest, r)
+			errs[i] = err
+			r.CloseWithError(err)
+		}()
+	}
+	_, readErr := io.Copy(fanout, src)
+	for _, w := range fanout.writers {
+		w.CloseWithError(readErr)
+	}
+	wg.Wait()
+	for i := range errs {
+		if errs[i] == nil {
+			errs[i] = fanout.errs[i]
+		}
+		if errs[i] == nil && readErr != nil {
+			errs[i] = readErr
+		}
+	}
+	return errs
+}
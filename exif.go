@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	exifTagDateTimeOriginal = 0x9003
+	exifTagExifIFD          = 0x8769
+	exifDateLayout          = "2006:01:02 15:04:05"
+	exifScanLimit           = 1 << 20
+	exifMaxEntries          = 1024
+)
+
+var exifExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".jpe": true, ".tif": true, ".tiff": true,
+	".heic": true, ".heif": true, ".avif": true,
+	".cr2": true, ".cr3": true, ".nef": true, ".nrw": true, ".arw": true, ".sr2": true,
+	".dng": true, ".orf": true, ".rw2": true, ".pef": true, ".raf": true, ".srw": true,
+}
+
+func readExifDate(path string) (time.Time, bool) {
+	if !exifExtensions[strings.ToLower(filepath.Ext(path))] {
+		return time.Time{}, false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+	head := make([]byte, 16)
+	if n, _ := io.ReadFull(f, head); n < 16 {
+		return time.Time{}, false
+	}
+	switch {
+	case head[0] == 0xFF && head[1] == 0xD8:
+		return jpegExifDate(f, 0)
+	case bytes.HasPrefix(head, []byte("II")) || bytes.HasPrefix(head, []byte("MM")):
+		return tiffExifDate(f, 0)
+	case bytes.HasPrefix(head, []byte("FUJIFILMCCD-RAW")):
+		var offset [4]byte
+		if _, err := f.ReadAt(offset[:], 84); err != nil {
+			return time.Time{}, false
+		}
+		return jpegExifDate(f, int64(binary.BigEndian.Uint32(offset[:])))
+	case string(head[4:8]) == "ftyp":
+		return scanExifDate(f)
+	}
+	return time.Time{}, false
+}
+func jpegExifDate(r io.ReaderAt, start int64) (time.Time, bool) {
+	offset := start + 2
+	var marker [4]byte
+	for {
+		if _, err := r.ReadAt(marker[:], offset); err != nil || marker[0] != 0xFF {
+			return time.Time{}, false
+		}
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return time.Time{}, false
+		}
+		length := int64(binary.BigEndian.Uint16(marker[2:]))
+		if marker[1] == 0xE1 {
+			var id [6]byte
+			if _, err := r.ReadAt(id[:], offset+4); err == nil && string(id[:]) == "Exif\x00\x00" {
+				return tiffExifDate(r, offset+10)
+			}
+		}
+		offset += 2 + length
+	}
+}
+func scanExifDate(r io.ReaderAt) (time.Time, bool) {
+	buf := make([]byte, exifScanLimit)
+	n, _ := r.ReadAt(buf, 0)
+	buf = buf[:n]
+	for _, header := range [][]byte{[]byte("II*\x00"), []byte("MM\x00*")} {
+		for offset := 0; ; {
+			i := bytes.Index(buf[offset:], header)
+			if i < 0 {
+				break
+			}
+			if t, ok := tiffExifDate(r, int64(offset+i)); ok {
+				return t, true
+			}
+			offset += i + 1
+		}
+	}
+	return time.Time{}, false
+}
+func tiffExifDate(r io.ReaderAt, base int64) (time.Time, bool) {
+	var header [8]byte
+	if _, err := r.ReadAt(header[:], base); err != nil {
+		return time.Time{}, false
+	}
+	var order binary.ByteOrder
+	switch string(header[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}, false
+	}
+	return ifdExifDate(r, base, int64(order.Uint32(header[4:])), order, 0)
+}
+func ifdExifDate(r io.ReaderAt, base, offset int64, order binary.ByteOrder, depth int) (time.Time, bool) {
+	var countBuf [2]byte
+	if depth > 2 || offset < 8 {
+		return time.Time{}, false
+	}
+	if _, err := r.ReadAt(countBuf[:], base+offset); err != nil {
+		return time.Time{}, false
+	}
+	count := int(order.Uint16(countBuf[:]))
+	if count == 0 || count > exifMaxEntries {
+		return time.Time{}, false
+	}
+	entries := make([]byte, count*12)
+	if _, err := r.ReadAt(entries, base+offset+2); err != nil {
+		return time.Time{}, false
+	}
+	var exifIFD int64
+	for i := 0; i < count; i++ {
+		entry := entries[i*12 : i*12+12]
+		tag := order.Uint16(entry)
+		switch tag {
+		case exifTagDateTimeOriginal:
+			value := make([]byte, 19)
+			if _, err := r.ReadAt(value, base+int64(order.Uint32(entry[8:]))); err != nil {
+				return time.Time{}, false
+			}
+			t, err := time.ParseInLocation(exifDateLayout, string(value), time.Local)
+			return t, err == nil
+		case exifTagExifIFD:
+			exifIFD = int64(order.Uint32(entry[8:]))
+		}
+	}
+	if exifIFD > 0 {
+		return ifdExifDate(r, base, exifIFD, order, depth+1)
+	}
+	return time.Time{}, false
+}
+func (c Config) dateFilterSource() string {
+	if c.UseExifDate {
+		return "EXIF, else modified"
+	}
+	return "modified"
+}
+func fileDate(path string, info fs.FileInfo, useExif bool) time.Time {
+	if useExif {
+		if t, ok := readExifDate(path); ok {
+			return t
+		}
+	}
+	return info.ModTime()
+}
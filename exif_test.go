@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+type exifTestOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+func exifTestTIFF(order exifTestOrder, date string, nested bool) []byte {
+	mark := "II"
+	if order == binary.BigEndian {
+		mark = "MM"
+	}
+	b := order.AppendUint16([]byte(mark), 42)
+	b = order.AppendUint32(b, 8)
+	entry := func(b []byte, tag, kind uint16, count, value uint32) []byte {
+		b = order.AppendUint16(b, tag)
+		b = order.AppendUint16(b, kind)
+		b = order.AppendUint32(b, count)
+		return order.AppendUint32(b, value)
+	}
+	if nested {
+		b = order.AppendUint16(b, 1)
+		b = entry(b, exifTagExifIFD, 4, 1, 26)
+		b = order.AppendUint32(b, 0)
+	}
+	b = order.AppendUint16(b, 2)
+	b = entry(b, 0x010F, 2, 1, 0)
+	b = entry(b, exifTagDateTimeOriginal, 2, 20, uint32(len(b)+12+4))
+	b = order.AppendUint32(b, 0)
+	return append(b, date+"\x00"...)
+}
+func exifTestJPEG(tiff []byte) []byte {
+	b := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00, 0xFF, 0xE1}
+	b = binary.BigEndian.AppendUint16(b, uint16(2+6+len(tiff)))
+	b = append(b, "Exif\x00\x00"...)
+	return append(append(b, tiff...), 0xFF, 0xD9)
+}
+func TestExifDate(t *testing.T) {
+	want := time.Date(2023, 7, 14, 9, 30, 5, 0, time.Local)
+	date := "2023:07:14 09:30:05"
+	selfLoop := exifTestTIFF(binary.LittleEndian, date, true)
+	binary.LittleEndian.PutUint32(selfLoop[18:], 8)
+	tests := []struct {
+		name string
+		read func(data []byte) (time.Time, bool)
+		data []byte
+		ok   bool
+	}{
+		{"tiff little-endian in IFD0", tiffReader, exifTestTIFF(binary.LittleEndian, date, false), true},
+		{"tiff big-endian in IFD0", tiffReader, exifTestTIFF(binary.BigEndian, date, false), true},
+		{"tiff little-endian in Exif IFD", tiffReader, exifTestTIFF(binary.LittleEndian, date, true), true},
+		{"tiff big-endian in Exif IFD", tiffReader, exifTestTIFF(binary.BigEndian, date, true), true},
+		{"jpeg after APP0", jpegReader, exifTestJPEG(exifTestTIFF(binary.BigEndian, date, true)), true},
+		{"jpeg without APP1", jpegReader, []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02}, false},
+		{"embedded tiff found by scan", scanReader, append([]byte("\x00\x00\x00\x18ftypheic....junk"), exifTestTIFF(binary.LittleEndian, date, true)...), true},
+		{"bad byte order", tiffReader, append([]byte("XX"), exifTestTIFF(binary.LittleEndian, date, false)[2:]...), false},
+		{"truncated entries", tiffReader, exifTestTIFF(binary.LittleEndian, date, false)[:20], false},
+		{"truncated date", tiffReader, exifTestTIFF(binary.LittleEndian, date, false)[:40], false},
+		{"malformed date", tiffReader, exifTestTIFF(binary.LittleEndian, "2023-07-14 09:30:05", false), false},
+		{"Exif IFD pointing back to IFD0", tiffReader, selfLoop, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.read(tt.data)
+		if ok != tt.ok || (ok && !got.Equal(want)) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, ok, want, tt.ok)
+		}
+	}
+}
+func tiffReader(data []byte) (time.Time, bool) { return tiffExifDate(bytes.NewReader(data), 0) }
+func jpegReader(data []byte) (time.Time, bool) { return jpegExifDate(bytes.NewReader(data), 0) }
+func scanReader(data []byte) (time.Time, bool) { return scanExifDate(bytes.NewReader(data)) }
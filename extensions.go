@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const exactNamePrefix = "="
+
+var compoundExtensions = []string{
+	".tar.gz",
+	".tar.bz2",
+	".tar.xz",
+	".tar.zst",
+	".tar.lz",
+	".tar.lz4",
+	".tar.lzma",
+	".tar.z",
+	".nii.gz",
+}
+
+var extensionAliases = [][]string{
+	{".jpg", ".jpeg", ".jpe", ".jfif"},
+	{".tif", ".tiff"},
+	{".htm", ".html"},
+	{".heic", ".heif"},
+	{".mpg", ".mpeg"},
+	{".aif", ".aiff"},
+	{".mid", ".midi"},
+	{".yml", ".yaml"},
+	{".tgz", ".tar.gz"},
+	{".tbz2", ".tar.bz2"},
+	{".txz", ".tar.xz"},
+	{".md", ".markdown"},
+}
+
+type extensionMatcher struct {
+	extensions map[string]bool
+	names      map[string]bool
+	compound   []string
+}
+
+func (c Config) newExtensionMatcher() extensionMatcher {
+	em := extensionMatcher{
+		extensions: map[string]bool{},
+		names:      map[string]bool{},
+		compound:   append([]string{}, compoundExtensions...),
+	}
+	for _, ext := range c.Extensions {
+		if name, ok := strings.CutPrefix(ext, exactNamePrefix); ok {
+			em.names[strings.ToLower(name)] = true
+			continue
+		}
+		for _, alias := range expandAliases(ext) {
+			em.extensions[alias] = true
+			if strings.Count(alias, ".") > 1 && !slices.Contains(em.compound, alias) {
+				em.compound = append(em.compound, alias)
+			}
+		}
+	}
+	sort.Slice(em.compound, func(i, j int) bool {
+		return len(em.compound[i]) > len(em.compound[j])
+	})
+	return em
+}
+func expandAliases(ext string) []string {
+	ext = strings.ToLower(ext)
+	for _, group := range extensionAliases {
+		if slices.Contains(group, ext) {
+			return group
+		}
+	}
+	return []string{ext}
+}
+func (em extensionMatcher) fileExtension(name string) string {
+	lower := strings.ToLower(name)
+	for _, suffix := range em.compound {
+		if strings.HasSuffix(lower, suffix) && len(lower) > len(suffix) {
+			return suffix
+		}
+	}
+	return filepath.Ext(lower)
+}
+func (em extensionMatcher) match(name, ext string) bool {
+	return em.extensions[ext] || em.names[strings.ToLower(name)]
+}
+func validateExtensionInput(input string) []string {
+	var problems []string
+	segments := strings.Split(input, ",")
+	if strings.TrimSpace(input) != "" {
+		for i, segment := range segments {
+			if strings.TrimSpace(segment) == "" && i < len(segments)-1 {
+				problems = append(problems, fmt.Sprintf("empty entry #%d", i+1))
+			}
+		}
+	}
+	for _, token := range strings.FieldsFunc(input, func(c rune) bool {
+		return c == ',' || c == ' '
+	}) {
+		switch {
+		case token == exactNamePrefix:
+			problems = append(problems, fmt.Sprintf("%q: missing file name", token))
+		case token == ".":
+			problems = append(problems, fmt.Sprintf("%q: missing extension", token))
+		case strings.Contains(token, ".."):
+			problems = append(problems, fmt.Sprintf("%q: double dot", token))
+		case strings.HasSuffix(token, "."):
+			problems = append(problems, fmt.Sprintf("%q: trailing dot", token))
+		case strings.ContainsAny(token, `/\`):
+			problems = append(problems, fmt.Sprintf("%q: path separators are not allowed", token))
+		case strings.ContainsAny(token, "*?["):
+			problems = append(problems, fmt.Sprintf("%q: wildcards are not supported", token))
+		}
+	}
+	return problems
+}
+func extensionSuggestions(field textField, stats []extensionStat, limit int) []extensionStat {
+	_, token := field.currentToken()
+	token = strings.ToLower(token)
+	if token != "" && !strings.HasPrefix(token, ".") && !strings.HasPrefix(token, exactNamePrefix) {
+		token = "." + token
+	}
+	used := map[string]bool{}
+	for _, ext := range strings.FieldsFunc(strings.ToLower(field.String()), func(c rune) bool {
+		return c == ',' || c == ' '
+	}) {
+		if !strings.HasPrefix(ext, ".") && !strings.HasPrefix(ext, exactNamePrefix) {
+			ext = "." + ext
+		}
+		used[ext] = true
+	}
+	var suggestions []extensionStat
+	for _, stat := range stats {
+		if used[stat.ext] || !strings.HasPrefix(strings.ToLower(stat.ext), token) {
+			continue
+		}
+		suggestions = append(suggestions, stat)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type scanFilter struct {
+	minSize int64
+	maxSize int64
+	after   time.Time
+	before  time.Time
+	useExif bool
+}
+
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"kib", 1 << 10},
+	{"mib", 1 << 20},
+	{"gib", 1 << 30},
+	{"tib", 1 << 40},
+	{"kb", 1000},
+	{"mb", 1000 * 1000},
+	{"gb", 1000 * 1000 * 1000},
+	{"tb", 1000 * 1000 * 1000 * 1000},
+	{"k", 1000},
+	{"m", 1000 * 1000},
+	{"g", 1000 * 1000 * 1000},
+	{"t", 1000 * 1000 * 1000 * 1000},
+	{"b", 1},
+}
+
+func parseSize(input string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if s == "" {
+		return 0, nil
+	}
+	factor := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			factor = unit.factor
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q (examples: 500KB, 10MB, 2GiB)", input)
+	}
+	return int64(value * float64(factor)), nil
+}
+func exactSize(size int64) string {
+	best, suffix := size, "B"
+	for _, unit := range sizeUnits {
+		if len(unit.suffix) > 1 && size%unit.factor == 0 && size/unit.factor < best {
+			best, suffix = size/unit.factor, strings.Replace(strings.ToUpper(unit.suffix), "IB", "iB", 1)
+		}
+	}
+	return strconv.FormatInt(best, 10) + suffix
+}
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"02.01.2006",
+}
+
+func parseDateSpec(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(input), time.Local); err == nil {
+			return t, nil
+		}
+	}
+	units := []struct {
+		suffix string
+		apply  func(n int) time.Time
+	}{
+		{"mo", func(n int) time.Time { return now.AddDate(0, -n, 0) }},
+		{"h", func(n int) time.Time { return now.Add(-time.Duration(n) * time.Hour) }},
+		{"d", func(n int) time.Time { return now.AddDate(0, 0, -n) }},
+		{"w", func(n int) time.Time { return now.AddDate(0, 0, -7*n) }},
+		{"y", func(n int) time.Time { return now.AddDate(-n, 0, 0) }},
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, unit.suffix))
+			if err == nil && n >= 0 {
+				return unit.apply(n), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (examples: 2024-01-31, 7d, 2w, 3mo, 1y)", input)
+}
+func (c Config) scanFilter(now time.Time) (scanFilter, error) {
+	var f scanFilter
+	var err error
+	f.minSize = c.MinSize
+	f.maxSize = c.MaxSize
+	f.useExif = c.UseExifDate
+	if f.after, err = parseDateSpec(c.ModifiedAfter, now); err != nil {
+		return f, err
+	}
+	if f.before, err = parseDateSpec(c.ModifiedBefore, now); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+func (f scanFilter) active() bool {
+	return f.minSize > 0 || f.maxSize > 0 || !f.after.IsZero() || !f.before.IsZero()
+}
+func (f scanFilter) match(path string, d fs.DirEntry) bool {
+	if !f.active() {
+		return true
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	if f.minSize > 0 && info.Size() < f.minSize {
+		return false
+	}
+	if f.maxSize > 0 && info.Size() > f.maxSize {
+		return false
+	}
+	if f.after.IsZero() && f.before.IsZero() {
+		return true
+	}
+	date := fileDate(path, info, f.useExif)
+	if !f.after.IsZero() && date.Before(f.after) {
+		return false
+	}
+	if !f.before.IsZero() && !date.Before(f.before) {
+		return false
+	}
+	return true
+}
+func getSizeDisplay(size int64) string {
+	if size <= 0 {
+		return "any"
+	}
+	return formatSize(size)
+}
+func getDateDisplay(spec string) string {
+	if spec == "" {
+		return "any"
+	}
+	return spec
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+		ok    bool
+	}{
+		{"", 0, true},
+		{"  ", 0, true},
+		{"0", 0, true},
+		{"512", 512, true},
+		{"512b", 512, true},
+		{"500KB", 500_000, true},
+		{"500 kb", 500_000, true},
+		{"10M", 10_000_000, true},
+		{"10MB", 10_000_000, true},
+		{"1.5GB", 1_500_000_000, true},
+		{"2T", 2_000_000_000_000, true},
+		{"4KiB", 4096, true},
+		{"10MiB", 10 << 20, true},
+		{"2gib", 2 << 30, true},
+		{"1TiB", 1 << 40, true},
+		{"-1", 0, false},
+		{"-5MB", 0, false},
+		{"MB", 0, false},
+		{"ten", 0, false},
+		{"10XB", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.input)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v, want %d (ok=%v)", tt.input, got, err, tt.want, tt.ok)
+		}
+	}
+}
+func TestExactSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0B"},
+		{999, "999B"},
+		{1000, "1KB"},
+		{1024, "1KiB"},
+		{1500, "1500B"},
+		{10_000_000, "10MB"},
+		{10 << 20, "10MiB"},
+		{1_500_000_000, "1500MB"},
+		{3 << 40, "3TiB"},
+	}
+	for _, tt := range tests {
+		got := exactSize(tt.size)
+		if got != tt.want {
+			t.Errorf("exactSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+		if back, err := parseSize(got); err != nil || back != tt.size {
+			t.Errorf("parseSize(exactSize(%d)) = %d, %v, want %d", tt.size, back, err, tt.size)
+		}
+	}
+}
+func TestParseDateSpec(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		input string
+		want  time.Time
+		ok    bool
+	}{
+		{"", time.Time{}, true},
+		{"2024-01-31", time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local), true},
+		{" 2024-01-31 ", time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local), true},
+		{"2024-01-31 08:30", time.Date(2024, 1, 31, 8, 30, 0, 0, time.Local), true},
+		{"2024-01-31 08:30:15", time.Date(2024, 1, 31, 8, 30, 15, 0, time.Local), true},
+		{"2024-01-31T08:30:00Z", time.Date(2024, 1, 31, 8, 30, 0, 0, time.UTC), true},
+		{"31.01.2024", time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local), true},
+		{"0d", now, true},
+		{"12h", now.Add(-12 * time.Hour), true},
+		{"7d", time.Date(2024, 3, 24, 12, 0, 0, 0, time.Local), true},
+		{"7D", time.Date(2024, 3, 24, 12, 0, 0, 0, time.Local), true},
+		{"2w", time.Date(2024, 3, 17, 12, 0, 0, 0, time.Local), true},
+		{"1mo", time.Date(2024, 2, 31, 12, 0, 0, 0, time.Local), true},
+		{"1y", time.Date(2023, 3, 31, 12, 0, 0, 0, time.Local), true},
+		{"-1d", time.Time{}, false},
+		{"d", time.Time{}, false},
+		{"7m", time.Time{}, false},
+		{"2024-13-01", time.Time{}, false},
+		{"yesterday", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseDateSpec(tt.input, now)
+		if (err == nil) != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseDateSpec(%q) = %v, %v, want %v (ok=%v)", tt.input, got, err, tt.want, tt.ok)
+		}
+	}
+}
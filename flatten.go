@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultPathSeparator = "__"
+	maxNameBytes         = 255
+)
+
+func (c Config) pathSeparator() string {
+	if c.PathSeparator == "" {
+		return defaultPathSeparator
+	}
+	return c.PathSeparator
+}
+func (c Config) pathEncodedName(rel, base string) string {
+	segments := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	if segments[0] == "." {
+		segments = nil
+	}
+	segments = segments[min(c.PathTrimDepth, len(segments)):]
+	return truncateName(strings.Join(append(segments, base), c.pathSeparator()))
+}
+func truncateName(name string) string {
+	if len(name) <= maxNameBytes {
+		return name
+	}
+	stem, ext := splitExtension(name)
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:4])
+	keep := max(maxNameBytes-len(hash)-1-len(ext), 0)
+	start := max(len(stem)-keep, 0)
+	for start < len(stem) && !utf8.RuneStart(stem[start]) {
+		start++
+	}
+	return hash + "~" + stem[start:] + ext
+}
+func parseTrimDepth(input string) (int, error) {
+	if input == "" {
+		return 0, nil
+	}
+	depth, err := strconv.Atoi(input)
+	if err != nil || depth < 0 {
+		return 0, fmt.Errorf("invalid depth %q: use 0 or a positive number", input)
+	}
+	return depth, nil
+}
+func (c Config) copyModeDisplay() string {
+	if !c.FlattenPaths {
+		return "flat (all files in one folder)"
+	}
+	example := c.pathEncodedName(filepath.Join("docs", "api", "readme.txt"), "readme.txt")
+	return fmt.Sprintf("flat, folder path in name (docs/api/readme.txt → %s)", example)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *model) folderList() *[]string {
+	if m.state == stateDests {
+		return &m.config.DestDirs
+	}
+	return &m.config.SourceDirs
+}
+func (m *model) addDestDir(path string) {
+	m.recentDests = pushRecent(m.recentDests, path)
+	for _, dest := range m.config.DestDirs {
+		if canonicalRoot(dest) == canonicalRoot(path) {
+			m.message = fmt.Sprintf("%s is already a destination folder", path)
+			return
+		}
+	}
+	m.config.DestDirs = append(m.config.DestDirs, path)
+}
+func (m model) updateFolderList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	list := m.folderList()
+	menuIndex := 0
+	selectState := stateSourceSelect
+	if m.state == stateDests {
+		menuIndex = 1
+		selectState = stateDestSelect
+	}
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(*list)+1 {
+			m.cursor++
+		}
+	case "d", "delete":
+		if m.cursor < len(*list) {
+			*list = slices.Delete(slices.Clone(*list), m.cursor, m.cursor+1)
+			if m.cursor > 0 && m.cursor >= len(*list) {
+				m.cursor--
+			}
+		}
+	case "enter":
+		if m.cursor == len(*list) {
+			m.state = selectState
+			m.cursor = 0
+		} else if m.cursor == len(*list)+1 {
+			m.state = stateMenu
+			m.cursor = menuIndex + 1
+		}
+	case "backspace":
+		m.state = stateMenu
+		m.cursor = menuIndex
+	}
+	return m, nil
+}
+func (m model) viewFolderList() string {
+	var s strings.Builder
+	title, icon := "📂 Source folders", "📂 "
+	if m.state == stateDests {
+		title, icon = "📁 Destination folders (each receives a full copy)", "📁 "
+	}
+	s.WriteString(headerStyle.Render(title))
+	s.WriteString("\n\n")
+	items := slices.Clone(*m.folderList())
+	for i, dir := range items {
+		items[i] = icon + dir
+	}
+	if len(items) == 0 {
+		s.WriteString(infoStyle.Render("No folders yet"))
+		s.WriteString("\n\n")
+	}
+	items = append(items, "➕ Add folder...", "🔙 Back")
+	for i, item := range items {
+		style := normalStyle
+		if i == m.cursor {
+			style = selectedStyle
+		}
+		s.WriteString(style.Render(item))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n" + infoStyle.Render("d: remove selected folder"))
+	return s.String()
+}
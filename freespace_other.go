@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !freebsd && !dragonfly
+
+package main
+
+func freeSpace(dir string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,23 @@
+//go:build darwin
+
+package main
+
+import (
+	"bytes"
+
+	"golang.org/x/sys/unix"
+)
+
+func filesystemType(dir string) string {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return ""
+	}
+	switch name := string(bytes.TrimRight(st.Fstypename[:], "\x00")); name {
+	case "msdos":
+		return "vfat"
+	case "exfat", "ntfs", "smbfs", "apfs", "hfs":
+		return name
+	}
+	return ""
+}
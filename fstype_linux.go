@@ -0,0 +1,81 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const ntfs3SuperMagic = 0x7366746e
+
+func filesystemType(dir string) string {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return ""
+	}
+	switch uint32(st.Type) {
+	case unix.MSDOS_SUPER_MAGIC:
+		return "vfat"
+	case unix.EXFAT_SUPER_MAGIC:
+		return "exfat"
+	case ntfs3SuperMagic:
+		return "ntfs"
+	case unix.CIFS_SUPER_MAGIC, unix.SMB2_SUPER_MAGIC, unix.SMB_SUPER_MAGIC:
+		return "smb"
+	case unix.FUSE_SUPER_MAGIC:
+		return fuseFilesystemType(mountType(canonicalRoot(dir)))
+	}
+	return ""
+}
+func fuseFilesystemType(fsType string) string {
+	switch {
+	case strings.Contains(fsType, "exfat"):
+		return "exfat"
+	case strings.Contains(fsType, "ntfs"):
+		return "ntfs"
+	case fsType == "fuseblk":
+		return "windows"
+	}
+	return ""
+}
+func mountType(dir string) string {
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	best, bestType := "", ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields, rest, ok := strings.Cut(scanner.Text(), " - ")
+		mount := strings.Fields(fields)
+		info := strings.Fields(rest)
+		if !ok || len(mount) < 5 || len(info) < 1 {
+			continue
+		}
+		point := unescapeMountPath(mount[4])
+		if isWithin(dir, point) && len(point) >= len(best) {
+			best, bestType = point, info[0]
+		}
+	}
+	return bestType
+}
+func unescapeMountPath(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
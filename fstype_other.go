@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package main
+
+import "runtime"
+
+func filesystemType(dir string) string {
+	if runtime.GOOS == "windows" {
+		return "windows"
+	}
+	return ""
+}
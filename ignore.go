@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const ignoreFileName = ".ficoutignore"
+
+var junkPatterns = []string{
+	".git/",
+	".svn/",
+	".hg/",
+	"node_modules/",
+	"__pycache__/",
+	".Trash/",
+	".Trashes/",
+	".Spotlight-V100/",
+	".fseventsd/",
+	".TemporaryItems/",
+	"$RECYCLE.BIN/",
+	"System Volume Information/",
+	".DS_Store",
+	"._*",
+	"Thumbs.db",
+	"ehthumbs.db",
+	"desktop.ini",
+	"~$*",
+	".~lock.*#",
+	"*.part",
+	"*.partial",
+	"*.crdownload",
+	"*.download",
+	"*.tmp",
+	"*.swp",
+}
+
+type ignoreRule struct {
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	fold     bool
+}
+type ignoreMatcher struct {
+	rules       []ignoreRule
+	skipDirs    []string
+	readIgnores bool
+}
+
+func (c Config) newIgnoreMatcher() *ignoreMatcher {
+	im := &ignoreMatcher{
+		skipDirs:    c.SkipDirs,
+		readIgnores: c.UseIgnoreFiles,
+	}
+	if c.SkipJunk {
+		for _, pattern := range junkPatterns {
+			if rule, ok := parseIgnoreLine(pattern, ""); ok {
+				rule.fold = true
+				im.rules = append(im.rules, rule)
+			}
+		}
+	}
+	return im
+}
+func parseIgnoreLine(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.HasPrefix(line, "/") {
+		rule.anchored = true
+		line = strings.TrimLeft(line, "/")
+	} else if strings.Contains(line, "/") {
+		rule.anchored = true
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.pattern = line
+	return rule, true
+}
+func (im *ignoreMatcher) load(dir, rel string) {
+	if !im.readIgnores {
+		return
+	}
+	file, err := os.Open(filepath.Join(dir, ignoreFileName))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	if rel == "." {
+		rel = ""
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text(), rel); ok {
+			im.rules = append(im.rules, rule)
+		}
+	}
+}
+func (im *ignoreMatcher) skip(rel string, isDir bool) bool {
+	if im.readIgnores && !isDir && path.Base(rel) == ignoreFileName {
+		return true
+	}
+	if isDir {
+		name := path.Base(rel)
+		for _, dir := range im.skipDirs {
+			if strings.EqualFold(name, dir) {
+				return true
+			}
+		}
+	}
+	ignored := false
+	for _, rule := range im.rules {
+		if rule.match(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+	pattern := r.pattern
+	if r.fold {
+		pattern = strings.ToLower(pattern)
+		rel = strings.ToLower(rel)
+	}
+	if !r.anchored {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+func matchGlobSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchGlobSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], parts[1:])
+}
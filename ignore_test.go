@@ -0,0 +1,103 @@
+package main
+
+import "testing"
+
+func TestIgnoreMatcherSkip(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		base  string
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"unanchored matches at any depth", []string{"*.log"}, "", "a/b/x.log", false, true},
+		{"unanchored needs a name match", []string{"*.log"}, "", "a/b/x.txt", false, false},
+		{"leading slash anchors to root", []string{"/build"}, "", "build", true, true},
+		{"leading slash does not match deeper", []string{"/build"}, "", "src/build", true, false},
+		{"inner slash anchors", []string{"doc/*.txt"}, "", "doc/a.txt", false, true},
+		{"star does not cross slashes", []string{"doc/*.txt"}, "", "doc/x/a.txt", false, false},
+		{"inner slash does not match deeper", []string{"doc/*.txt"}, "", "x/doc/a.txt", false, false},
+		{"leading globstar at root", []string{"**/tmp"}, "", "tmp", true, true},
+		{"leading globstar at depth", []string{"**/tmp"}, "", "a/b/tmp", true, true},
+		{"middle globstar matches zero folders", []string{"a/**/b"}, "", "a/b", false, true},
+		{"middle globstar matches many folders", []string{"a/**/b"}, "", "a/x/y/b", false, true},
+		{"middle globstar keeps the tail", []string{"a/**/b"}, "", "a/x/c", false, false},
+		{"trailing globstar matches contents", []string{"a/**"}, "", "a/x/y", false, true},
+		{"dir-only matches folders", []string{"cache/"}, "", "x/cache", true, true},
+		{"dir-only skips files", []string{"cache/"}, "", "x/cache", false, false},
+		{"negation re-includes", []string{"*.log", "!keep.log"}, "", "keep.log", false, false},
+		{"negation leaves others ignored", []string{"*.log", "!keep.log"}, "", "drop.log", false, true},
+		{"last matching rule wins", []string{"!keep.log", "*.log"}, "", "keep.log", false, true},
+		{"escaped bang is literal", []string{`\!important`}, "", "!important", false, true},
+		{"escaped hash is literal", []string{`\#tag`}, "", "#tag", false, true},
+		{"comment is not a rule", []string{"#tag"}, "", "#tag", false, false},
+		{"trailing spaces are trimmed", []string{"*.bak  "}, "", "x.bak", false, true},
+		{"nested file applies below its folder", []string{"*.tmp"}, "sub", "sub/x/a.tmp", false, true},
+		{"nested file ignores other folders", []string{"*.tmp"}, "sub", "a.tmp", false, false},
+		{"nested anchor is relative to its folder", []string{"/x"}, "sub", "sub/x", false, true},
+		{"nested anchor does not match deeper", []string{"/x"}, "sub", "sub/y/x", false, false},
+		{"matching is case-sensitive", []string{"*.LOG"}, "", "x.log", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im := &ignoreMatcher{}
+			for _, line := range tt.lines {
+				if rule, ok := parseIgnoreLine(line, tt.base); ok {
+					im.rules = append(im.rules, rule)
+				}
+			}
+			if got := im.skip(tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("skip(%q, %v) with %q = %v, want %v", tt.rel, tt.isDir, tt.lines, got, tt.want)
+			}
+		})
+	}
+}
+func TestParseIgnoreLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want ignoreRule
+		ok   bool
+	}{
+		{"", ignoreRule{}, false},
+		{"   ", ignoreRule{}, false},
+		{"# comment", ignoreRule{}, false},
+		{"/", ignoreRule{}, false},
+		{"!", ignoreRule{}, false},
+		{"*.jpg", ignoreRule{pattern: "*.jpg"}, true},
+		{"!*.jpg", ignoreRule{pattern: "*.jpg", negate: true}, true},
+		{"out/", ignoreRule{pattern: "out", dirOnly: true}, true},
+		{"/out/", ignoreRule{pattern: "out", dirOnly: true, anchored: true}, true},
+		{"a/b", ignoreRule{pattern: "a/b", anchored: true}, true},
+		{"a/b\r", ignoreRule{pattern: "a/b", anchored: true}, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseIgnoreLine(tt.line, "")
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseIgnoreLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+func TestJunkAndSkipDirs(t *testing.T) {
+	im := Config{SkipJunk: true, SkipDirs: []string{"Backup"}}.newIgnoreMatcher()
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"THUMBS.DB", false, true},
+		{"a/.DS_Store", false, true},
+		{"a/._photo.jpg", false, true},
+		{"a/.git", true, true},
+		{"a/.git", false, false},
+		{"video.MP4.part", false, true},
+		{"photo.jpg", false, false},
+		{"x/backup", true, true},
+		{"x/backup", false, false},
+	}
+	for _, tt := range tests {
+		if got := im.skip(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("skip(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
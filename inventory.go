@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const inventoryPageSize = 15
+
+type extensionStat struct {
+	ext   string
+	count int
+	size  int64
+}
+type inventoryMsg struct {
+	stats []extensionStat
+	err   error
+}
+
+func (m model) buildInventory() ([]extensionStat, error) {
+	byExt := map[string]*extensionStat{}
+	extensions := m.config.newExtensionMatcher()
+	err := m.walkSource(func(file sourceFile, d fs.DirEntry) bool {
+		ext := extensions.fileExtension(d.Name())
+		if m.config.DetectType {
+			ext = realExtension(file.path, ext)
+		}
+		if ext == "" {
+			ext = exactNamePrefix + d.Name()
+		}
+		stat, ok := byExt[ext]
+		if !ok {
+			stat = &extensionStat{ext: ext}
+			byExt[ext] = stat
+		}
+		stat.count++
+		if info, err := d.Info(); err == nil {
+			stat.size += info.Size()
+		}
+		return true
+	}, nil, nil)
+	stats := make([]extensionStat, 0, len(byExt))
+	for _, stat := range byExt {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].size != stats[j].size {
+			return stats[i].size > stats[j].size
+		}
+		return stats[i].ext < stats[j].ext
+	})
+	return stats, err
+}
+func (m model) startInventory() tea.Cmd {
+	return func() tea.Msg {
+		stats, err := m.buildInventory()
+		return inventoryMsg{stats: stats, err: err}
+	}
+}
+func (m model) updateInventory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.inventory) {
+			m.cursor++
+		}
+	case " ":
+		if m.cursor < len(m.inventory) {
+			ext := m.inventory[m.cursor].ext
+			m.inventorySelected[ext] = !m.inventorySelected[ext]
+		}
+	case "a":
+		all := true
+		for _, stat := range m.inventory {
+			all = all && m.inventorySelected[stat.ext]
+		}
+		for _, stat := range m.inventory {
+			m.inventorySelected[stat.ext] = !all
+		}
+	case "enter":
+		if m.cursor < len(m.inventory) {
+			var selected []string
+			for _, stat := range m.inventory {
+				if m.inventorySelected[stat.ext] {
+					selected = append(selected, stat.ext)
+				}
+			}
+			if len(selected) == 0 {
+				m.message = "Select at least one extension with Space"
+				return m, nil
+			}
+			m.config.Extensions = selected
+			m.state = stateMenu
+			m.cursor = 3
+		} else {
+			m.state = stateExtensions
+			m.cursor = len(m.presets) + 1
+		}
+	case "backspace":
+		m.state = stateExtensions
+		m.cursor = len(m.presets) + 1
+	}
+	return m, nil
+}
+func (m model) viewInventory() string {
+	var s strings.Builder
+	s.WriteString(headerStyle.Render(fmt.Sprintf("🔎 Extensions in %s", getSourcesDisplay(m.config.SourceDirs))))
+	s.WriteString("\n\n")
+	if m.inventory == nil {
+		s.WriteString(infoStyle.Render("Scanning source folder..."))
+		return s.String()
+	}
+	var totalCount int
+	var totalSize int64
+	for _, stat := range m.inventory {
+		totalCount += stat.count
+		totalSize += stat.size
+	}
+	start := 0
+	if m.cursor >= inventoryPageSize {
+		start = m.cursor - inventoryPageSize + 1
+	}
+	end := min(start+inventoryPageSize, len(m.inventory))
+	if start > 0 {
+		s.WriteString(infoStyle.Render(fmt.Sprintf("   ↑ %d more", start)))
+		s.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		stat := m.inventory[i]
+		check := "[ ]"
+		if m.inventorySelected[stat.ext] {
+			check = "[x]"
+		}
+		style := normalStyle
+		if i == m.cursor {
+			style = selectedStyle
+		}
+		s.WriteString(style.Render(fmt.Sprintf("%s %-14s %7d files %12s", check, stat.ext, stat.count, formatSize(stat.size))))
+		s.WriteString("\n")
+	}
+	if end < len(m.inventory) {
+		s.WriteString(infoStyle.Render(fmt.Sprintf("   ↓ %d more", len(m.inventory)-end)))
+		s.WriteString("\n")
+	}
+	backStyle := normalStyle
+	if m.cursor == len(m.inventory) {
+		backStyle = selectedStyle
+	}
+	s.WriteString(backStyle.Render("🔙 Back"))
+	s.WriteString("\n\n")
+	s.WriteString(infoStyle.Render(fmt.Sprintf("Total: %d files, %s in %d types", totalCount, formatSize(totalSize), len(m.inventory))))
+	s.WriteString("\n")
+	s.WriteString(infoStyle.Render("Space: toggle • a: toggle all • Enter: use selected"))
+	return s.String()
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type fileSignature struct {
+	ext    string
+	offset int
+	magic  []byte
+	check  func(header []byte) bool
+}
+
+var fileSignatures = []fileSignature{
+	{".jpg", 0, []byte{0xFF, 0xD8, 0xFF}, nil},
+	{".png", 0, []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}, nil},
+	{".gif", 0, []byte("GIF87a"), nil},
+	{".gif", 0, []byte("GIF89a"), nil},
+	{".bmp", 0, []byte("BM"), func(h []byte) bool { return len(h) >= 10 && bytes.Equal(h[6:10], []byte{0, 0, 0, 0}) }},
+	{".tif", 0, []byte{'I', 'I', 0x2A, 0x00}, nil},
+	{".tif", 0, []byte{'M', 'M', 0x00, 0x2A}, nil},
+	{".psd", 0, []byte("8BPS"), nil},
+	{".ico", 0, []byte{0x00, 0x00, 0x01, 0x00}, nil},
+	{".pdf", 0, []byte("%PDF-"), nil},
+	{".rtf", 0, []byte(`{\rtf`), nil},
+	{".doc", 0, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}, nil},
+	{".zip", 0, []byte{'P', 'K', 0x03, 0x04}, nil},
+	{".zip", 0, []byte{'P', 'K', 0x05, 0x06}, nil},
+	{".rar", 0, []byte{'R', 'a', 'r', '!', 0x1A, 0x07}, nil},
+	{".7z", 0, []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}, nil},
+	{".gz", 0, []byte{0x1F, 0x8B}, nil},
+	{".bz2", 0, []byte("BZh"), func(h []byte) bool { return len(h) >= 4 && h[3] >= '1' && h[3] <= '9' }},
+	{".xz", 0, []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}, nil},
+	{".zst", 0, []byte{0x28, 0xB5, 0x2F, 0xFD}, nil},
+	{".tar", 257, []byte("ustar"), nil},
+	{".mkv", 0, []byte{0x1A, 0x45, 0xDF, 0xA3}, nil},
+	{".flv", 0, []byte("FLV"), func(h []byte) bool { return len(h) >= 4 && h[3] == 0x01 }},
+	{".mp3", 0, []byte("ID3"), nil},
+	{".mp3", 0, []byte{0xFF, 0xFB}, nil},
+	{".mp3", 0, []byte{0xFF, 0xF3}, nil},
+	{".mp3", 0, []byte{0xFF, 0xF2}, nil},
+	{".flac", 0, []byte("fLaC"), nil},
+	{".ogg", 0, []byte("OggS"), nil},
+}
+
+var riffTypes = map[string]string{
+	"WEBP": ".webp",
+	"WAVE": ".wav",
+	"AVI ": ".avi",
+}
+
+var ftypBrands = map[string]string{
+	"heic": ".heic",
+	"heix": ".heic",
+	"hevc": ".heic",
+	"mif1": ".heic",
+	"msf1": ".heic",
+	"avif": ".avif",
+	"qt  ": ".mov",
+	"M4A ": ".m4a",
+	"M4B ": ".m4a",
+	"3gp4": ".3gp",
+	"3gp5": ".3gp",
+	"3g2a": ".3gp",
+	"crx ": ".cr3",
+}
+
+var typeFamilies = map[string][]string{
+	".jpg":  {".jpeg", ".jpe", ".jfif"},
+	".tif":  {".tiff", ".dng", ".cr2", ".nef", ".nrw", ".arw", ".srw", ".pef", ".orf", ".rw2", ".raf", ".3fr", ".erf", ".mos", ".kdc", ".dcr", ".sr2"},
+	".zip":  {".docx", ".xlsx", ".pptx", ".odt", ".ods", ".odp", ".epub", ".jar", ".apk", ".cbz", ".xpi", ".kmz", ".ipa"},
+	".doc":  {".xls", ".ppt", ".msg", ".msi", ".dot", ".xlt", ".pot"},
+	".mp4":  {".m4v", ".m4p"},
+	".mkv":  {".webm", ".mka", ".mk3d"},
+	".heic": {".heif", ".hif"},
+	".m4a":  {".m4b", ".mp4", ".aac"},
+	".3gp":  {".3g2", ".mp4"},
+	".mov":  {".qt"},
+	".ogg":  {".oga", ".ogv", ".opus", ".spx"},
+	".gz":   {".tgz", ".tar.gz", ".nii.gz"},
+	".bz2":  {".tbz2", ".tar.bz2"},
+	".xz":   {".txz", ".tar.xz"},
+	".zst":  {".tar.zst"},
+	".mp3":  {".mp2"},
+	".rar":  {".cbr"},
+	".7z":   {".cb7"},
+	".ico":  {".cur"},
+	".psd":  {".psb"},
+}
+
+func sniffType(header []byte) string {
+	if len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) {
+		if ext, ok := riffTypes[string(header[8:12])]; ok {
+			return ext
+		}
+	}
+	if len(header) >= 12 && bytes.Equal(header[4:8], []byte("ftyp")) {
+		if ext, ok := ftypBrands[string(header[8:12])]; ok {
+			return ext
+		}
+		return ".mp4"
+	}
+	for _, sig := range fileSignatures {
+		end := sig.offset + len(sig.magic)
+		if len(header) >= end && bytes.Equal(header[sig.offset:end], sig.magic) && (sig.check == nil || sig.check(header)) {
+			return sig.ext
+		}
+	}
+	return ""
+}
+func detectFileType(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	return sniffType(header[:n])
+}
+func extensionMatchesType(ext, detected string) bool {
+	if ext == detected {
+		return true
+	}
+	return slices.Contains(typeFamilies[detected], ext)
+}
+func realExtension(path, ext string) string {
+	detected := detectFileType(path)
+	if detected == "" || extensionMatchesType(ext, detected) {
+		return ext
+	}
+	return detected
+}
+func isKnownTypeExtension(ext string) bool {
+	for _, sig := range fileSignatures {
+		if sig.ext == ext {
+			return true
+		}
+	}
+	for known, members := range typeFamilies {
+		if known == ext || slices.Contains(members, ext) {
+			return true
+		}
+	}
+	for _, known := range riffTypes {
+		if known == ext {
+			return true
+		}
+	}
+	for _, known := range ftypBrands {
+		if known == ext {
+			return true
+		}
+	}
+	return false
+}
+func fixExtension(path, name string) string {
+	ext := filepath.Ext(name)
+	real := realExtension(path, strings.ToLower(ext))
+	if real == strings.ToLower(ext) {
+		return name
+	}
+	if isKnownTypeExtension(strings.ToLower(ext)) {
+		return strings.TrimSuffix(name, ext) + real
+	}
+	return name + real
+}
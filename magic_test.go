@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestSniffType(t *testing.T) {
+	tar := make([]byte, 512)
+	copy(tar[257:], "ustar")
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"text", []byte("hello, world"), ""},
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0}, ".jpg"},
+		{"png", []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}, ".png"},
+		{"png truncated", []byte{0x89, 'P', 'N', 'G'}, ""},
+		{"gif", []byte("GIF89a"), ".gif"},
+		{"tiff little-endian", []byte{'I', 'I', 0x2A, 0x00}, ".tif"},
+		{"tiff big-endian", []byte{'M', 'M', 0x00, 0x2A}, ".tif"},
+		{"pdf", []byte("%PDF-1.7"), ".pdf"},
+		{"zip", []byte{'P', 'K', 0x03, 0x04}, ".zip"},
+		{"bmp", []byte{'B', 'M', 1, 2, 3, 4, 0, 0, 0, 0}, ".bmp"},
+		{"bmp needs reserved zeros", []byte{'B', 'M', 1, 2, 3, 4, 5, 0, 0, 0}, ""},
+		{"bmp too short", []byte("BM"), ""},
+		{"bzip2", []byte("BZh9"), ".bz2"},
+		{"bzip2 needs a block size", []byte("BZhx"), ""},
+		{"tar", tar, ".tar"},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), ".webp"},
+		{"wav", []byte("RIFF\x00\x00\x00\x00WAVEfmt "), ".wav"},
+		{"unknown riff", []byte("RIFF\x00\x00\x00\x00XXXX"), ""},
+		{"heic", []byte("\x00\x00\x00\x18ftypheic"), ".heic"},
+		{"quicktime", []byte("\x00\x00\x00\x14ftypqt  "), ".mov"},
+		{"unknown ftyp brand", []byte("\x00\x00\x00\x18ftypisom"), ".mp4"},
+		{"mp3 with id3", []byte("ID3\x04"), ".mp3"},
+		{"mp3 frame", []byte{0xFF, 0xFB, 0x90}, ".mp3"},
+	}
+	for _, tt := range tests {
+		if got := sniffType(tt.header); got != tt.want {
+			t.Errorf("%s: sniffType = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+func TestExtensionMatchesType(t *testing.T) {
+	tests := []struct {
+		ext, detected string
+		want          bool
+	}{
+		{".jpg", ".jpg", true},
+		{".jpeg", ".jpg", true},
+		{".cr2", ".tif", true},
+		{".docx", ".zip", true},
+		{".png", ".jpg", false},
+		{".jpg", ".png", false},
+		{".mp4", ".m4a", true},
+	}
+	for _, tt := range tests {
+		if got := extensionMatchesType(tt.ext, tt.detected); got != tt.want {
+			t.Errorf("extensionMatchesType(%q, %q) = %v, want %v", tt.ext, tt.detected, got, tt.want)
+		}
+	}
+}
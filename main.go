@@ -1,14 +1,20 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -16,45 +22,98 @@ import (
 
 type state int
 
+const (
+	menuItemCount   = 9
+	optionsPageSize = 10
+	confirmPageSize = 5
+)
+
 const (
 	stateMenu state = iota
+	stateSources
 	stateSourceSelect
+	stateDests
 	stateDestSelect
 	stateDriveSelect
 	stateBrowseSource
 	stateBrowseDest
 	stateExtensions
 	stateCustomExtensions
+	stateInventory
+	stateEditPreset
+	stateSaveProfile
 	stateOptions
+	stateEditOption
 	stateConfirm
 	stateCopying
 	stateComplete
 )
 
 type model struct {
-	state        state
-	cursor       int
-	config       Config
-	currentPath  string
-	directories  []string
-	message      string
-	progress     int
-	totalFiles   int
-	copiedFiles  int
-	currentFile  string
-	customInput  string
-	err          error
-	quitting     bool
-	progressChan chan copyProgressMsg
-	driveContext string
+	state             state
+	cursor            int
+	config            Config
+	currentPath       string
+	directories       []string
+	message           string
+	progress          int
+	totalFiles        int
+	copiedFiles       int
+	currentFile       string
+	input             textField
+	editField         string
+	inventory         []extensionStat
+	inventorySelected map[string]bool
+	presets           []extensionPreset
+	presetSelected    map[string]bool
+	presetIndex       int
+	presetField       int
+	nameInput         textField
+	pendingDelete     int
+	pendingUndo       bool
+	profiles          []string
+	recentSources     []string
+	recentDests       []string
+	stats             jobStats
+	templateSamples   []templateSample
+	confirmScroll     int
+	chunks            map[string]*chunkState
+	planned           map[string]*plannedDir
+	err               error
+	quitting          bool
+	progressChan      chan copyProgressMsg
+	driveContext      string
 }
 type Config struct {
-	SourceDir  string
-	DestDir    string
-	Extensions []string
-	Recursive  bool
-	Verbose    bool
-	DryRun     bool
+	SourceDirs      []string    `json:"source_dirs"`
+	DestDirs        []string    `json:"dest_dirs"`
+	DestMode        string      `json:"dest_mode,omitempty"`
+	Extensions      []string    `json:"extensions"`
+	Recursive       bool        `json:"recursive"`
+	Verbose         bool        `json:"verbose"`
+	DryRun          bool        `json:"dry_run"`
+	MinSize         int64       `json:"min_size,omitempty"`
+	MaxSize         int64       `json:"max_size,omitempty"`
+	ModifiedAfter   string      `json:"modified_after,omitempty"`
+	ModifiedBefore  string      `json:"modified_before,omitempty"`
+	DetectType      bool        `json:"detect_type"`
+	FixExtension    bool        `json:"fix_extension"`
+	UseIgnoreFiles  bool        `json:"use_ignore_files"`
+	SkipJunk        bool        `json:"skip_junk"`
+	SkipDirs        []string    `json:"skip_dirs,omitempty"`
+	NameTemplate    string      `json:"name_template,omitempty"`
+	FlattenPaths    bool        `json:"flatten_paths"`
+	PathSeparator   string      `json:"path_separator,omitempty"`
+	PathTrimDepth   int         `json:"path_trim_depth,omitempty"`
+	Incremental     bool        `json:"incremental"`
+	SyncHash        bool        `json:"sync_hash"`
+	BucketBy        string      `json:"bucket_by,omitempty"`
+	BucketSize      int         `json:"bucket_size,omitempty"`
+	Routes          []routeRule `json:"routes,omitempty"`
+	UseExifDate     bool        `json:"use_exif_date"`
+	Sidecars        bool        `json:"sidecars"`
+	NameReplacement string      `json:"name_replacement,omitempty"`
+	Symlinks        string      `json:"symlinks,omitempty"`
 }
 
 var (
@@ -112,24 +171,42 @@ type copyCompleteMsg struct {
 	success bool
 	copied  int
 	total   int
+	stats   jobStats
 }
 type tickMsg time.Time
 type startCopyMsg struct {
-	files []string
+	files   []sourceFile
+	skipped []string
 }
 
 func initialModel() model {
 	wd, _ := os.Getwd()
+	presets, err := loadPresets()
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
 	return model{
-		state:        stateMenu,
-		currentPath:  wd,
-		progressChan: make(chan copyProgressMsg, 100),
-		config: Config{
-			Extensions: []string{".jpg", ".png", ".pdf"},
-			Recursive:  true,
-			Verbose:    false,
-			DryRun:     false,
-		},
+		state:          stateMenu,
+		currentPath:    wd,
+		progressChan:   make(chan copyProgressMsg, 100),
+		message:        message,
+		presets:        presets,
+		presetSelected: map[string]bool{},
+		pendingDelete:  -1,
+		profiles:       listProfiles(),
+		config:         defaultConfig(),
+	}
+}
+func defaultConfig() Config {
+	return Config{
+		Extensions:     []string{".jpg", ".png", ".pdf"},
+		Recursive:      true,
+		Verbose:        false,
+		DryRun:         false,
+		UseIgnoreFiles: true,
+		SkipJunk:       true,
+		UseExifDate:    true,
 	}
 }
 func (m model) Init() tea.Cmd {
@@ -139,9 +216,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
+		case "esc":
+			if !m.isTextInput() {
+				m.quitting = true
+				return m, tea.Quit
+			}
 		case "q":
 			if m.state == stateComplete {
 				m.quitting = true
@@ -151,6 +233,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch m.state {
 		case stateMenu:
 			return m.updateMenu(msg)
+		case stateSources, stateDests:
+			return m.updateFolderList(msg)
 		case stateSourceSelect:
 			return m.updateSourceSelect(msg)
 		case stateDestSelect:
@@ -163,8 +247,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateExtensions(msg)
 		case stateCustomExtensions:
 			return m.updateCustomExtensions(msg)
+		case stateInventory:
+			return m.updateInventory(msg)
+		case stateEditPreset:
+			return m.updateEditPreset(msg)
+		case stateSaveProfile:
+			return m.updateSaveProfile(msg)
 		case stateOptions:
 			return m.updateOptions(msg)
+		case stateEditOption:
+			return m.updateEditOption(msg)
 		case stateConfirm:
 			return m.updateConfirm(msg)
 		case stateCopying:
@@ -180,6 +272,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = stateComplete
 		m.copiedFiles = msg.copied
 		m.totalFiles = msg.total
+		m.stats = msg.stats
 		return m, nil
 	case tickMsg:
 		if m.state == stateCopying {
@@ -187,27 +280,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case startCopyMsg:
-		return m, m.processFiles(msg.files)
+		return m, m.processFiles(msg.files, msg.skipped)
+	case inventoryMsg:
+		if msg.err != nil {
+			m.message = msg.err.Error()
+		}
+		m.inventory = msg.stats
+		return m, nil
+	case templateSamplesMsg:
+		if msg.err != nil {
+			m.message = msg.err.Error()
+		}
+		m.templateSamples = msg.samples
+		return m, nil
 	}
 	return m, nil
 }
+func (m model) isTextInput() bool {
+	return m.state == stateCustomExtensions || m.state == stateEditOption || m.state == stateEditPreset ||
+		m.state == stateSaveProfile
+}
 func (m model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pendingUndo && (msg.String() != "enter" || m.cursor != 6) {
+		m.pendingUndo = false
+		m.message = ""
+	}
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
 			m.cursor--
 		}
 	case "down", "j":
-		if m.cursor < 5 {
+		if m.cursor < menuItemCount+len(m.profiles)-1 {
 			m.cursor++
 		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(msg.String()[0] - '1'); i < len(m.profiles) {
+			return m.loadProfileIntoModel(m.profiles[i]), nil
+		}
 	case "enter":
+		if m.cursor >= menuItemCount {
+			return m.loadProfileIntoModel(m.profiles[m.cursor-menuItemCount]), nil
+		}
 		switch m.cursor {
 		case 0:
-			m.state = stateSourceSelect
+			m.state = stateSources
 			m.cursor = 0
 		case 1:
-			m.state = stateDestSelect
+			m.state = stateDests
 			m.cursor = 0
 		case 2:
 			m.state = stateExtensions
@@ -215,13 +335,21 @@ func (m model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.state = stateOptions
 			m.cursor = 0
 		case 4:
-			if m.config.SourceDir != "" && m.config.DestDir != "" {
+			if len(m.config.SourceDirs) > 0 && len(m.config.DestDirs) > 0 {
 				m.state = stateConfirm
 				m.cursor = 0
+				m.confirmScroll = 0
 			} else {
 				m.message = "Please select source and destination folders first!"
 			}
 		case 5:
+			m.state = stateSaveProfile
+			m.input = newTextField("")
+		case 6:
+			return m.undoFromMenu()
+		case 7:
+			return m.resetToDefaults(), nil
+		case 8:
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -229,7 +357,7 @@ func (m model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 func (m model) updateSourceSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	shortcuts := getShortcuts()
+	shortcuts := m.shortcutsFor(m.recentSources)
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -246,26 +374,26 @@ func (m model) updateSourceSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.state = stateDriveSelect
 				m.cursor = 0
 			} else if shortcuts[m.cursor].path != "" {
-				m.config.SourceDir = shortcuts[m.cursor].path
-				m.state = stateMenu
-				m.cursor = 1
+				m.addSourceDir(shortcuts[m.cursor].path)
+				m.state = stateSources
+				m.cursor = len(m.config.SourceDirs)
 			}
 		} else if m.cursor == len(shortcuts)-2 {
 			m.state = stateBrowseSource
 			m.cursor = 0
 			m.directories = getDirectories(m.currentPath)
 		} else {
-			m.state = stateMenu
-			m.cursor = 0
+			m.state = stateSources
+			m.cursor = len(m.config.SourceDirs)
 		}
 	case "backspace":
-		m.state = stateMenu
-		m.cursor = 0
+		m.state = stateSources
+		m.cursor = len(m.config.SourceDirs)
 	}
 	return m, nil
 }
 func (m model) updateDestSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	shortcuts := getShortcuts()
+	shortcuts := m.shortcutsFor(m.recentDests)
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -282,21 +410,21 @@ func (m model) updateDestSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.state = stateDriveSelect
 				m.cursor = 0
 			} else if shortcuts[m.cursor].path != "" {
-				m.config.DestDir = shortcuts[m.cursor].path
-				m.state = stateMenu
-				m.cursor = 2
+				m.addDestDir(shortcuts[m.cursor].path)
+				m.state = stateDests
+				m.cursor = len(m.config.DestDirs)
 			}
 		} else if m.cursor == len(shortcuts)-2 {
 			m.state = stateBrowseDest
 			m.cursor = 0
 			m.directories = getDirectories(m.currentPath)
 		} else {
-			m.state = stateMenu
-			m.cursor = 1
+			m.state = stateDests
+			m.cursor = len(m.config.DestDirs)
 		}
 	case "backspace":
-		m.state = stateMenu
-		m.cursor = 1
+		m.state = stateDests
+		m.cursor = len(m.config.DestDirs)
 	}
 	return m, nil
 }
@@ -358,13 +486,13 @@ func (m model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		if m.cursor == 0 {
 			if m.state == stateBrowseSource {
-				m.config.SourceDir = m.currentPath
-				m.state = stateMenu
-				m.cursor = 0
+				m.addSourceDir(m.currentPath)
+				m.state = stateSources
+				m.cursor = len(m.config.SourceDirs)
 			} else {
-				m.config.DestDir = m.currentPath
-				m.state = stateMenu
-				m.cursor = 1
+				m.addDestDir(m.currentPath)
+				m.state = stateDests
+				m.cursor = len(m.config.DestDirs)
 			}
 		} else if len(options) > 1 && m.cursor == 1 && options[1] == "⬆️  Up" {
 			m.currentPath = filepath.Dir(m.currentPath)
@@ -402,14 +530,12 @@ func (m model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 func (m model) updateExtensions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	presets := [][]string{
-		{".jpg", ".png", ".gif", ".bmp"},
-		{".pdf", ".doc", ".docx", ".txt"},
-		{".mp4", ".avi", ".mkv", ".mov"},
-		{".mp3", ".wav", ".flac", ".m4a"},
-		{".zip", ".rar", ".7z", ".tar"},
-	}
-	maxCursor := len(presets) + 1
+	presets := m.presets
+	maxCursor := len(presets) + 3
+	if m.pendingDelete >= 0 && msg.String() != "d" {
+		m.pendingDelete = -1
+		m.message = ""
+	}
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -419,15 +545,54 @@ func (m model) updateExtensions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.cursor < maxCursor {
 			m.cursor++
 		}
+	case " ":
+		if m.cursor < len(presets) {
+			name := presets[m.cursor].Name
+			m.presetSelected[name] = !m.presetSelected[name]
+		}
+	case "e":
+		if m.cursor < len(presets) {
+			return m.openPresetEditor(m.cursor)
+		}
+	case "n":
+		return m.openPresetEditor(-1)
+	case "d":
+		if m.cursor < len(presets) {
+			if m.pendingDelete == m.cursor {
+				m.pendingDelete = -1
+				return m.deletePreset(m.cursor), nil
+			}
+			m.pendingDelete = m.cursor
+			m.message = fmt.Sprintf("Press d again to delete preset %q", presets[m.cursor].Name)
+		}
 	case "enter":
 		if m.cursor < len(presets) {
-			m.config.Extensions = presets[m.cursor]
+			m.config.Extensions = m.selectedPresetExtensions()
 			m.state = stateMenu
 			m.cursor = 3
 		} else if m.cursor == len(presets) {
+			return m.openPresetEditor(-1)
+		} else if m.cursor == len(presets)+1 {
+			if len(m.config.SourceDirs) == 0 {
+				m.message = "Please select a source folder first!"
+				return m, nil
+			}
+			m.state = stateInventory
+			m.inventory = nil
+			m.inventorySelected = map[string]bool{}
+			for _, ext := range m.config.Extensions {
+				m.inventorySelected[ext] = true
+			}
+			m.cursor = 0
+			return m, m.startInventory()
+		} else if m.cursor == len(presets)+2 {
 			m.state = stateCustomExtensions
-			m.customInput = strings.Join(m.config.Extensions, ", ")
+			m.input = newTextField(strings.Join(m.config.Extensions, ", "))
 			m.cursor = 0
+			if len(m.config.SourceDirs) > 0 {
+				m.inventory = nil
+				return m, m.startInventory()
+			}
 		} else {
 			m.state = stateMenu
 			m.cursor = 2
@@ -441,30 +606,24 @@ func (m model) updateExtensions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) updateCustomExtensions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
-		m.config.Extensions = m.parseExtensions(m.customInput)
+		if problems := validateExtensionInput(m.input.String()); len(problems) > 0 {
+			m.message = "Please fix: " + strings.Join(problems, "; ")
+			return m, nil
+		}
+		m.message = ""
+		m.config.Extensions = m.parseExtensions(m.input.String())
 		m.state = stateMenu
 		m.cursor = 3
-	case "backspace":
-		if len(m.customInput) > 0 {
-			m.customInput = m.customInput[:len(m.customInput)-1]
+	case "tab":
+		if suggestions := extensionSuggestions(m.input, m.inventory, 1); len(suggestions) > 0 {
+			m.input.completeToken(suggestions[0].ext)
 		}
 	case "esc":
+		m.message = ""
 		m.state = stateExtensions
-		m.cursor = len([][]string{
-			{".jpg", ".png", ".gif", ".bmp"},
-			{".pdf", ".doc", ".docx", ".txt"},
-			{".mp4", ".avi", ".mkv", ".mov"},
-			{".mp3", ".wav", ".flac", ".m4a"},
-			{".zip", ".rar", ".7z", ".tar"},
-		})
+		m.cursor = len(m.presets) + 2
 	default:
-		if len(msg.String()) == 1 {
-			char := msg.String()
-			if (char >= "a" && char <= "z") || (char >= "A" && char <= "Z") ||
-				(char >= "0" && char <= "9") || char == "." || char == "," || char == " " {
-				m.customInput += char
-			}
-		}
+		m.input.update(msg)
 	}
 	return m, nil
 }
@@ -478,36 +637,89 @@ func (m model) parseExtensions(input string) []string {
 	var extensions []string
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
-		if part != "" {
-			if !strings.HasPrefix(part, ".") {
-				part = "." + part
-			}
-			extensions = append(extensions, strings.ToLower(part))
+		if part == "" || part == exactNamePrefix {
+			continue
+		}
+		if strings.HasPrefix(part, exactNamePrefix) {
+			extensions = append(extensions, part)
+			continue
 		}
+		if !strings.HasPrefix(part, ".") {
+			part = "." + part
+		}
+		extensions = append(extensions, strings.ToLower(part))
 	}
 	return extensions
 }
+
+type optionItem struct {
+	label  string
+	value  string
+	toggle func(c *Config)
+	field  string
+}
+
+func (m model) optionItems() []optionItem {
+	return []optionItem{
+		{label: "💽 Destination mode", value: getDestModeDisplay(m.config.DestMode), toggle: func(c *Config) {
+			if c.DestMode == destModeSpill {
+				c.DestMode = destModeMirror
+			} else {
+				c.DestMode = destModeSpill
+			}
+		}},
+		{label: "🔁 Only new or changed files", value: getBoolDisplay(m.config.Incremental), toggle: func(c *Config) { c.Incremental = !c.Incremental }},
+		{label: "🔁 Compare contents by hash", value: getBoolDisplay(m.config.SyncHash), toggle: func(c *Config) { c.SyncHash = !c.SyncHash }},
+		{label: "🔀 Route file types to folders", value: getRoutesDisplay(m.config.Routes), field: "routes"},
+		{label: "🔍 Search in subfolders", value: getBoolDisplay(m.config.Recursive), toggle: func(c *Config) { c.Recursive = !c.Recursive }},
+		{label: "📝 Verbose output", value: getBoolDisplay(m.config.Verbose), toggle: func(c *Config) { c.Verbose = !c.Verbose }},
+		{label: "🧪 Dry run mode", value: getBoolDisplay(m.config.DryRun), toggle: func(c *Config) { c.DryRun = !c.DryRun }},
+		{label: "📏 Minimum size", value: getSizeDisplay(m.config.MinSize), field: "minsize"},
+		{label: "📏 Maximum size", value: getSizeDisplay(m.config.MaxSize), field: "maxsize"},
+		{label: "📅 Date after (" + m.config.dateFilterSource() + ")", value: getDateDisplay(m.config.ModifiedAfter), field: "after"},
+		{label: "📅 Date before (" + m.config.dateFilterSource() + ")", value: getDateDisplay(m.config.ModifiedBefore), field: "before"},
+		{label: "🔗 Symbolic links", value: getSymlinkDisplay(m.config.symlinkPolicy()), toggle: func(c *Config) { c.Symlinks = nextSymlinkPolicy(c.symlinkPolicy()) }},
+		{label: "📎 Bring sidecar files along", value: getBoolDisplay(m.config.Sidecars), toggle: func(c *Config) { c.Sidecars = !c.Sidecars }},
+		{label: "📸 Photo date from EXIF", value: getBoolDisplay(m.config.UseExifDate), toggle: func(c *Config) { c.UseExifDate = !c.UseExifDate }},
+		{label: "🧬 Detect type by content", value: getBoolDisplay(m.config.DetectType), toggle: func(c *Config) { c.DetectType = !c.DetectType }},
+		{label: "🏷️  Fix extension on copy", value: getBoolDisplay(m.config.FixExtension), toggle: func(c *Config) { c.FixExtension = !c.FixExtension }},
+		{label: "🙈 Use " + ignoreFileName + " files", value: getBoolDisplay(m.config.UseIgnoreFiles), toggle: func(c *Config) { c.UseIgnoreFiles = !c.UseIgnoreFiles }},
+		{label: "🧹 Skip junk files", value: getBoolDisplay(m.config.SkipJunk), toggle: func(c *Config) { c.SkipJunk = !c.SkipJunk }},
+		{label: "🚫 Skip folders named", value: getListDisplay(m.config.SkipDirs), field: "skipdirs"},
+		{label: "🧭 Folder path in file name", value: getBoolDisplay(m.config.FlattenPaths), toggle: func(c *Config) { c.FlattenPaths = !c.FlattenPaths }},
+		{label: "🧭 Path separator", value: m.config.pathSeparator(), field: "pathsep"},
+		{label: "🧭 Leading folders to drop", value: strconv.Itoa(m.config.PathTrimDepth), field: "pathtrim"},
+		{label: "🗂️  Split into subfolders", value: getBucketDisplay(m.config), toggle: func(c *Config) { c.BucketBy = nextBucketMode(c.BucketBy) }},
+		{label: "🗂️  Files per numbered folder", value: strconv.Itoa(m.config.bucketSize()), field: "bucketsize"},
+		{label: "✏️  Replace invalid characters with", value: m.config.nameReplacement(), field: "replacement"},
+		{label: "🏷️  File name template", value: getTemplateDisplay(m.config.NameTemplate), field: "template"},
+	}
+}
 func (m model) updateOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.optionItems()
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
 			m.cursor--
 		}
 	case "down", "j":
-		if m.cursor < 3 {
+		if m.cursor < len(items) {
 			m.cursor++
 		}
 	case "enter":
-		switch m.cursor {
-		case 0:
-			m.config.Recursive = !m.config.Recursive
-		case 1:
-			m.config.Verbose = !m.config.Verbose
-		case 2:
-			m.config.DryRun = !m.config.DryRun
-		case 3:
+		if m.cursor == len(items) {
 			m.state = stateMenu
 			m.cursor = 4
+		} else if item := items[m.cursor]; item.toggle != nil {
+			item.toggle(&m.config)
+		} else if item.field != "" {
+			m.editField = item.field
+			m.input = newTextField(m.optionFieldInput(item.field))
+			m.state = stateEditOption
+			if item.field == "template" {
+				m.templateSamples = nil
+				return m, m.loadTemplateSamples()
+			}
 		}
 	case "backspace":
 		m.state = stateMenu
@@ -515,12 +727,129 @@ func (m model) updateOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
+func (m model) optionFieldInput(field string) string {
+	switch field {
+	case "minsize":
+		if m.config.MinSize > 0 {
+			return exactSize(m.config.MinSize)
+		}
+	case "maxsize":
+		if m.config.MaxSize > 0 {
+			return exactSize(m.config.MaxSize)
+		}
+	case "after":
+		return m.config.ModifiedAfter
+	case "before":
+		return m.config.ModifiedBefore
+	case "skipdirs":
+		return strings.Join(m.config.SkipDirs, ", ")
+	case "template":
+		return m.config.NameTemplate
+	case "pathsep":
+		return m.config.pathSeparator()
+	case "pathtrim":
+		return strconv.Itoa(m.config.PathTrimDepth)
+	case "bucketsize":
+		return strconv.Itoa(m.config.bucketSize())
+	case "routes":
+		return formatRoutes(m.config.Routes)
+	case "replacement":
+		return m.config.nameReplacement()
+	}
+	return ""
+}
+func (m *model) setOptionField(field, input string) error {
+	input = strings.TrimSpace(input)
+	switch field {
+	case "minsize", "maxsize":
+		size, err := parseSize(strings.ReplaceAll(input, " ", ""))
+		if err != nil {
+			return err
+		}
+		if field == "minsize" {
+			m.config.MinSize = size
+		} else {
+			m.config.MaxSize = size
+		}
+	case "after", "before":
+		if _, err := parseDateSpec(input, time.Now()); err != nil {
+			return err
+		}
+		if field == "after" {
+			m.config.ModifiedAfter = input
+		} else {
+			m.config.ModifiedBefore = input
+		}
+	case "skipdirs":
+		m.config.SkipDirs = nil
+		for _, name := range strings.Split(input, ",") {
+			if name = strings.Trim(strings.TrimSpace(name), "/"); name != "" {
+				m.config.SkipDirs = append(m.config.SkipDirs, name)
+			}
+		}
+	case "pathsep":
+		if strings.ContainsAny(input, `/\`) {
+			return fmt.Errorf("separator cannot contain / or \\")
+		}
+		m.config.PathSeparator = input
+	case "pathtrim":
+		depth, err := parseTrimDepth(input)
+		if err != nil {
+			return err
+		}
+		m.config.PathTrimDepth = depth
+	case "replacement":
+		if strings.ContainsAny(input, windowsReservedChars) || strings.ContainsFunc(input, unicode.IsControl) {
+			return fmt.Errorf("replacement cannot contain %s", windowsReservedChars)
+		}
+		m.config.NameReplacement = input
+	case "routes":
+		routes, err := parseRoutes(input)
+		if err != nil {
+			return err
+		}
+		m.config.Routes = routes
+	case "bucketsize":
+		size, err := strconv.Atoi(input)
+		if input != "" && (err != nil || size < 1) {
+			return fmt.Errorf("invalid number of files %q", input)
+		}
+		m.config.BucketSize = size
+	case "template":
+		if _, err := parseNameTemplate(input); err != nil {
+			return err
+		}
+		m.config.NameTemplate = input
+	}
+	return nil
+}
+func (m model) updateEditOption(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if err := m.setOptionField(m.editField, m.input.String()); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		m.message = ""
+		m.state = stateOptions
+	case "esc":
+		m.message = ""
+		m.state = stateOptions
+	default:
+		m.input.update(msg)
+	}
+	return m, nil
+}
 func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "left", "h":
 		m.cursor = 0
 	case "right", "l":
 		m.cursor = 1
+	case "up", "k":
+		m.confirmScroll = max(m.confirmScroll-1, 0)
+	case "down", "j":
+		m.confirmScroll = min(m.confirmScroll+1, max(len(m.confirmLines())-confirmPageSize, 0))
 	case "enter":
 		if m.cursor == 0 {
 			m.state = stateCopying
@@ -539,50 +868,51 @@ func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) startCopying() tea.Cmd {
 	return tea.Batch(
 		func() tea.Msg {
-			files, err := m.scanFiles()
+			files, skipped, err := m.scanPlan(0)
 			if err != nil {
 				return copyCompleteMsg{success: false, copied: 0, total: 0}
 			}
-			return startCopyMsg{files: files}
+			return startCopyMsg{files: files, skipped: skipped}
 		},
 		m.tickCmd(),
 	)
 }
-func (m model) processFiles(files []string) tea.Cmd {
+func (m model) processFiles(files []sourceFile, skipped []string) tea.Cmd {
 	return func() tea.Msg {
 		m.totalFiles = len(files)
-		copied := 0
+		stats := newJobStats(len(files))
+		stats.notCopied = skipped
+		job := m.newCopyJob()
 		progressChan := make(chan copyProgressMsg, 10)
 		go func() {
 			defer close(progressChan)
 			for i, file := range files {
 				progress := ((i + 1) * 100) / len(files)
-				currentFile := filepath.Base(file)
+				currentFile := filepath.Base(file.path)
 				progressChan <- copyProgressMsg{
 					file:     currentFile,
 					progress: progress,
 					total:    len(files),
-					copied:   copied,
+					copied:   stats.copied,
 				}
 				if !m.config.DryRun {
-					err := m.copyFile(file)
-					if err == nil {
-						copied++
-					}
+					stats.record(file, job.copy(file))
 				} else {
-					copied++
+					stats.record(file, m.dryRunResults(file))
 					time.Sleep(50 * time.Millisecond)
 				}
 			}
+			if !m.config.DryRun {
+				stats.manifestErr = job.finish()
+			}
 		}()
-		var lastProgress copyProgressMsg
-		for progress := range progressChan {
-			lastProgress = progress
+		for range progressChan {
 		}
 		return copyCompleteMsg{
 			success: true,
-			copied:  lastProgress.copied,
+			copied:  stats.copied,
 			total:   len(files),
+			stats:   stats,
 		}
 	}
 }
@@ -591,51 +921,180 @@ func (m model) tickCmd() tea.Cmd {
 		return tickMsg(t)
 	})
 }
-func (m model) scanFiles() ([]string, error) {
-	var files []string
-	err := filepath.WalkDir(m.config.SourceDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
+func (m model) walkSource(visit func(file sourceFile, d fs.DirEntry) bool, report func(file sourceFile, d fs.DirEntry, reason string), kept func(path string)) error {
+	filter, err := m.config.scanFilter(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, root := range m.config.sourceRoots() {
+		if err := m.walkRoot(root, filter, visit, report, kept); errors.Is(err, errWalkStopped) {
 			return nil
+		} else if err != nil {
+			return err
 		}
-		if d.IsDir() {
-			return nil
+	}
+	return nil
+}
+func (m model) scanFiles() ([]sourceFile, error) {
+	files, _, err := m.scanPlan(0)
+	return files, err
+}
+func (m model) scanPlan(limit int) ([]sourceFile, []string, error) {
+	var files []sourceFile
+	var skipped []string
+	kept := map[string]bool{}
+	extensions := m.config.newExtensionMatcher()
+	err := m.walkSource(func(file sourceFile, d fs.DirEntry) bool {
+		ext := extensions.fileExtension(d.Name())
+		if m.config.DetectType && !file.link {
+			ext = realExtension(file.path, ext)
 		}
-		ext := strings.ToLower(filepath.Ext(d.Name()))
-		for _, allowedExt := range m.config.Extensions {
-			if ext == allowedExt {
-				files = append(files, path)
-				break
-			}
+		if extensions.match(d.Name(), ext) {
+			file.index = len(files) + 1
+			files = append(files, file)
 		}
-		return nil
+		return limit == 0 || len(files) < limit
+	}, func(file sourceFile, d fs.DirEntry, reason string) {
+		if extensions.match(d.Name(), extensions.fileExtension(d.Name())) {
+			skipped = append(skipped, file.rel+": "+reason)
+		}
+	}, func(path string) {
+		kept[path] = true
 	})
-	return files, err
+	if m.config.Sidecars {
+		attachSidecars(files, func(path string) bool { return kept[path] })
+	}
+	return files, skipped, err
 }
-func (m model) copyFile(srcPath string) error {
-	fileName := filepath.Base(srcPath)
-	destPath := filepath.Join(m.config.DestDir, fileName)
-	destPath = m.resolveFileConflict(destPath)
-	if err := os.MkdirAll(m.config.DestDir, 0755); err != nil {
-		return err
+func (m model) baseFileName(file sourceFile) string {
+	fileName := filepath.Base(file.path)
+	if m.config.DetectType && m.config.FixExtension {
+		fileName = fixExtension(file.path, fileName)
+	}
+	if m.config.FlattenPaths {
+		fileName = m.config.pathEncodedName(file.rel, fileName)
+	}
+	return fileName
+}
+func (m model) destFileName(file sourceFile) (string, error) {
+	if m.config.NameTemplate == "" {
+		return m.baseFileName(file), nil
 	}
-	sourceFile, err := os.Open(srcPath)
+	parts, err := parseNameTemplate(m.config.NameTemplate)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer sourceFile.Close()
-	destFile, err := os.Create(destPath)
+	dates, hash := templateNeeds(parts)
+	facts, err := loadTemplateFacts(file, dates, hash)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer destFile.Close()
-	_, err = io.Copy(destFile, sourceFile)
-	return err
+	return renderNameTemplate(parts, file, m.baseFileName(file), m.config.pathSeparator(), facts)
 }
-func (m model) resolveFileConflict(destPath string) string {
+func (m model) dryRunResults(file sourceFile) []copyResult {
+	dests, routed := m.destsFor(file)
+	if !routed && m.config.DestMode == destModeSpill {
+		dests = dests[:1]
+	}
+	results := make([]copyResult, len(dests))
+	for i, dest := range dests {
+		results[i] = copyResult{dest: dest}
+	}
+	return results
+}
+func (m model) copyFile(file sourceFile) []copyResult {
+	dests, _ := m.destsFor(file)
+	return m.copyTo(file, dests)
+}
+func (m model) copyTo(file sourceFile, dests []string) []copyResult {
+	fileName, nameErr := m.destFileName(file)
+	results := make([]copyResult, len(dests))
+	var targets []*os.File
+	var targetIndex []int
+	for i, dest := range dests {
+		results[i].dest = dest
+		if nameErr != nil {
+			results[i].err = nameErr
+			continue
+		}
+		bucket, err := m.bucketDir(file, dest, fileName)
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			results[i].err = err
+			continue
+		}
+		rel := filepath.Join(bucket, fileName)
+		if safe := sanitizeRelPath(rel, filesystemType(dest), m.config.nameReplacement()); safe != rel {
+			results[i].renamedFrom = rel
+			rel = safe
+		}
+		target := filepath.Join(dest, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			results[i].err = err
+			continue
+		}
+		results[i].path = m.resolveFileConflict(target, file.sidecarSuffixes()...)
+		if file.link {
+			continue
+		}
+		destFile, err := os.Create(results[i].path)
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+		targets = append(targets, destFile)
+		targetIndex = append(targetIndex, i)
+	}
+	if file.link {
+		copyLink(file, results)
+		for i := range results {
+			if result := &results[i]; result.err == nil && len(file.sidecars) > 0 {
+				result.sidecars, result.sidecarErr = copySidecars(file, result.path)
+			}
+		}
+		return results
+	}
+	if len(targets) == 0 {
+		return results
+	}
+	sourceFile, err := os.Open(file.path)
+	var errs []error
+	if err != nil {
+		errs = make([]error, len(targets))
+		for i := range errs {
+			errs[i] = err
+		}
+	} else {
+		hash := sha256.New()
+		errs = writeToAll(io.TeeReader(sourceFile, hash), targets)
+		sourceFile.Close()
+		sum := hex.EncodeToString(hash.Sum(nil))
+		for _, i := range targetIndex {
+			results[i].hash = sum
+		}
+	}
+	for i, target := range targets {
+		if closeErr := target.Close(); errs[i] == nil {
+			errs[i] = closeErr
+		}
+		if errs[i] != nil {
+			os.Remove(target.Name())
+			results[targetIndex[i]].err = errs[i]
+		} else if len(file.sidecars) > 0 {
+			result := &results[targetIndex[i]]
+			result.sidecars, result.sidecarErr = copySidecars(file, result.path)
+		}
+	}
+	return results
+}
+func (m model) resolveFileConflict(destPath string, sidecarSuffixes ...string) string {
 	originalPath := destPath
 	counter := 1
 	for {
-		if _, err := os.Stat(destPath); os.IsNotExist(err) {
+		if !slices.ContainsFunc(append(sidecarPaths(destPath, sidecarSuffixes), destPath), m.nameTaken) {
 			break
 		}
 		dir := filepath.Dir(originalPath)
@@ -645,6 +1104,10 @@ func (m model) resolveFileConflict(destPath string) string {
 		destPath = filepath.Join(dir, fmt.Sprintf("%s_%d%s", nameWithoutExt, counter, ext))
 		counter++
 	}
+	m.reserveName(destPath)
+	for _, path := range sidecarPaths(destPath, sidecarSuffixes) {
+		m.reserveName(path)
+	}
 	return destPath
 }
 func (m model) View() string {
@@ -657,6 +1120,8 @@ func (m model) View() string {
 	switch m.state {
 	case stateMenu:
 		s.WriteString(m.viewMenu())
+	case stateSources, stateDests:
+		s.WriteString(m.viewFolderList())
 	case stateSourceSelect:
 		s.WriteString(m.viewSourceSelect())
 	case stateDestSelect:
@@ -669,8 +1134,16 @@ func (m model) View() string {
 		s.WriteString(m.viewExtensions())
 	case stateCustomExtensions:
 		s.WriteString(m.viewCustomExtensions())
+	case stateInventory:
+		s.WriteString(m.viewInventory())
+	case stateEditPreset:
+		s.WriteString(m.viewEditPreset())
+	case stateSaveProfile:
+		s.WriteString(m.viewSaveProfile())
 	case stateOptions:
 		s.WriteString(m.viewOptions())
+	case stateEditOption:
+		s.WriteString(m.viewEditOption())
 	case stateConfirm:
 		s.WriteString(m.viewConfirm())
 	case stateCopying:
@@ -690,11 +1163,14 @@ func (m model) viewMenu() string {
 	s.WriteString(headerStyle.Render("📋 Main Menu"))
 	s.WriteString("\n\n")
 	items := []string{
-		fmt.Sprintf("📂 Source folder: %s", getDisplayPath(m.config.SourceDir)),
-		fmt.Sprintf("📁 Destination folder: %s", getDisplayPath(m.config.DestDir)),
+		fmt.Sprintf("📂 Source folders: %s", getSourcesDisplay(m.config.SourceDirs)),
+		fmt.Sprintf("📁 Destination folders: %s", getSourcesDisplay(m.config.DestDirs)),
 		fmt.Sprintf("📄 File formats: %s", strings.Join(m.config.Extensions, ", ")),
 		"⚙️  Additional settings",
 		"🚀 Start copying",
+		"💾 Save as profile...",
+		"↩️  Undo last job",
+		"♻️  Reset to defaults",
 		"🚪 Exit",
 	}
 	for i, item := range items {
@@ -705,13 +1181,30 @@ func (m model) viewMenu() string {
 		s.WriteString(style.Render(item))
 		s.WriteString("\n")
 	}
+	if len(m.profiles) > 0 {
+		s.WriteString("\n" + headerStyle.Render("🗂️  Profiles") + "\n")
+		for i, name := range m.profiles {
+			style := normalStyle
+			if menuItemCount+i == m.cursor {
+				style = selectedStyle
+			}
+			label := "   " + name
+			if i < 9 {
+				label = fmt.Sprintf("%d. %s", i+1, name)
+			}
+			s.WriteString(style.Render(label))
+			s.WriteString("\n")
+		}
+		s.WriteString(infoStyle.Render("1-9: load profile"))
+		s.WriteString("\n")
+	}
 	return s.String()
 }
 func (m model) viewSourceSelect() string {
-	return m.viewDirectorySelect("📂 Select source folder")
+	return m.viewDirectorySelect("📂 Select source folder", m.shortcutsFor(m.recentSources))
 }
 func (m model) viewDestSelect() string {
-	return m.viewDirectorySelect("📁 Select destination folder")
+	return m.viewDirectorySelect("📁 Select destination folder", m.shortcutsFor(m.recentDests))
 }
 func (m model) viewDriveSelect() string {
 	var s strings.Builder
@@ -739,11 +1232,10 @@ func (m model) viewDriveSelect() string {
 	s.WriteString("\n")
 	return s.String()
 }
-func (m model) viewDirectorySelect(title string) string {
+func (m model) viewDirectorySelect(title string, shortcuts []shortcut) string {
 	var s strings.Builder
 	s.WriteString(headerStyle.Render(title))
 	s.WriteString("\n\n")
-	shortcuts := getShortcuts()
 	for i, shortcut := range shortcuts {
 		style := normalStyle
 		if i == m.cursor {
@@ -778,27 +1270,33 @@ func (m model) viewExtensions() string {
 	var s strings.Builder
 	s.WriteString(headerStyle.Render("📄 Select file types"))
 	s.WriteString("\n\n")
-	presets := []struct {
-		label string
-		exts  []string
-	}{
-		{"🖼️  Images", []string{".jpg", ".png", ".gif", ".bmp"}},
-		{"📄 Documents", []string{".pdf", ".doc", ".docx", ".txt"}},
-		{"🎬 Video", []string{".mp4", ".avi", ".mkv", ".mov"}},
-		{"🎵 Audio", []string{".mp3", ".wav", ".flac", ".m4a"}},
-		{"📦 Archives", []string{".zip", ".rar", ".7z", ".tar"}},
-		{"✏️  Custom extensions", nil},
-		{"🔙 Back", nil},
-	}
-	for i, preset := range presets {
+	var items []string
+	for _, preset := range m.presets {
+		check := "[ ]"
+		if m.presetSelected[preset.Name] {
+			check = "[x]"
+		}
+		items = append(items, fmt.Sprintf("%s %s", check, preset.label()))
+	}
+	items = append(items,
+		"➕ New preset...",
+		"🔎 From source folder...",
+		"✏️  Custom extensions",
+		"🔙 Back",
+	)
+	for i, item := range items {
 		style := normalStyle
 		if i == m.cursor {
 			style = selectedStyle
 		}
-		s.WriteString(style.Render(preset.label))
+		s.WriteString(style.Render(item))
 		s.WriteString("\n")
 	}
+	if m.cursor < len(m.presets) {
+		s.WriteString("\n" + infoStyle.Render(strings.Join(m.presets[m.cursor].Extensions, ", ")))
+	}
 	s.WriteString("\n" + infoStyle.Render(fmt.Sprintf("Current: %s", strings.Join(m.config.Extensions, ", "))))
+	s.WriteString("\n" + infoStyle.Render("Space: toggle • Enter: apply checked presets • n: new • e: edit • d: delete"))
 	return s.String()
 }
 func (m model) viewCustomExtensions() string {
@@ -807,54 +1305,173 @@ func (m model) viewCustomExtensions() string {
 	s.WriteString("\n\n")
 	inputBox := boxStyle.Render(fmt.Sprintf(
 		"Enter file extensions separated by commas\n"+
-			"Example: .txt, .log, pdf, docx\n\n"+
-			"Input: %s|",
-		m.customInput))
+			"Example: .txt, .log, pdf, docx, .tar.gz\n"+
+			"Exact file names without extension: =Makefile, =README\n"+
+			"Aliases are matched automatically (.jpg = .jpeg = .jpe)\n\n"+
+			"Input: %s",
+		m.input.view(true)))
 	s.WriteString(inputBox)
-	s.WriteString("\n\n")
-	s.WriteString(infoStyle.Render("Enter: save • Backspace: delete character • Esc: cancel"))
+	s.WriteString("\n")
+	for _, problem := range validateExtensionInput(m.input.String()) {
+		s.WriteString(errorStyle.Render("✗ " + problem))
+		s.WriteString("\n")
+	}
+	s.WriteString(m.viewExtensionSuggestions())
+	s.WriteString("\n")
+	s.WriteString(infoStyle.Render(textFieldHelp + " • Tab: complete"))
 	s.WriteString("\n")
 	s.WriteString(infoStyle.Render("Current extensions: " + strings.Join(m.config.Extensions, ", ")))
 	return s.String()
 }
+func (m model) viewExtensionSuggestions() string {
+	if len(m.config.SourceDirs) == 0 {
+		return ""
+	}
+	if m.inventory == nil {
+		return infoStyle.Render("Scanning source folder for suggestions...") + "\n"
+	}
+	var items []string
+	for _, stat := range extensionSuggestions(m.input, m.inventory, 8) {
+		items = append(items, fmt.Sprintf("%s (%d)", stat.ext, stat.count))
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return infoStyle.Render("In source folder: "+strings.Join(items, ", ")) + "\n"
+}
 func (m model) viewOptions() string {
 	var s strings.Builder
 	s.WriteString(headerStyle.Render("⚙️ Additional settings"))
 	s.WriteString("\n\n")
-	options := []string{
-		fmt.Sprintf("🔍 Search in subfolders: %s", getBoolDisplay(m.config.Recursive)),
-		fmt.Sprintf("📝 Verbose output: %s", getBoolDisplay(m.config.Verbose)),
-		fmt.Sprintf("🧪 Dry run mode: %s", getBoolDisplay(m.config.DryRun)),
-		"🔙 Back",
+	var options []string
+	for _, item := range m.optionItems() {
+		options = append(options, fmt.Sprintf("%s: %s", item.label, item.value))
 	}
-	for i, option := range options {
+	options = append(options, "🔙 Back")
+	start := 0
+	if m.cursor >= optionsPageSize {
+		start = m.cursor - optionsPageSize + 1
+	}
+	end := min(start+optionsPageSize, len(options))
+	if start > 0 {
+		s.WriteString(infoStyle.Render(fmt.Sprintf("   ↑ %d more", start)))
+		s.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
 		style := normalStyle
 		if i == m.cursor {
 			style = selectedStyle
 		}
-		s.WriteString(style.Render(option))
+		s.WriteString(style.Render(options[i]))
+		s.WriteString("\n")
+	}
+	if end < len(options) {
+		s.WriteString(infoStyle.Render(fmt.Sprintf("   ↓ %d more", len(options)-end)))
 		s.WriteString("\n")
 	}
 	return s.String()
 }
-func (m model) viewConfirm() string {
+func (m model) viewEditOption() string {
 	var s strings.Builder
-	s.WriteString(headerStyle.Render("🚀 Operation confirmation"))
+	hints := map[string]string{
+		"minsize":     "Only copy files at least this large\nExample: 500KB, 10MB, 2GiB (KB/MB/GB and K/M/G = 1000, KiB/MiB/GiB = 1024; empty = any)",
+		"maxsize":     "Only copy files at most this large\nExample: 500KB, 10MB, 2GiB (KB/MB/GB and K/M/G = 1000, KiB/MiB/GiB = 1024; empty = any)",
+		"after":       "Only copy files dated on or after (" + m.config.dateFilterSource() + ")\nExample: 2024-01-31, 7d, 2w, 3mo, 1y (empty = any)",
+		"before":      "Only copy files dated before (" + m.config.dateFilterSource() + ")\nExample: 2024-01-31, 7d, 2w, 3mo, 1y (empty = any)",
+		"skipdirs":    "Folder names to skip entirely, separated by commas\nExample: build, vendor, .cache",
+		"pathsep":     "Joins folder names when the path is folded into the file name\nExample: __, -, + (empty = __)",
+		"pathtrim":    "Number of leading folders to leave out of the file name\nExample: 1 turns docs/api/readme.txt into api__readme.txt",
+		"replacement": "Used in file names for characters the destination filesystem does not allow\n(for example : ? \" | on FAT32, exFAT, NTFS or SMB shares). Example: _ or - (empty = _)",
+		"routes": "Send matching files to their own folder; everything else goes to the destination folders\n" +
+			"Separate rules with ; and file types with , (extensions, @Preset or patterns like IMG_*)\n" +
+			"Example: @Images -> ~/Pictures/inbox; pdf -> ~/Documents/inbox; mp4, mov -> /mnt/nas/video",
+		"bucketsize": "How many files go into each numbered folder when splitting by count\nExample: 1000 (empty = 1000)",
+		"template": "Name for copied files (empty = original name)\n" +
+			"Tokens: {name} {ext} {parent} {relpath} {source} {mtime:2006-01-02} {taken:2006-01-02} {counter:04} {hash:8}\n" +
+			"{taken} is the photo's EXIF shooting date, or the modification time without EXIF\n" +
+			"Example: {mtime:2006-01-02}_{parent}_{name}{ext}",
+	}
+	for _, item := range m.optionItems() {
+		if item.field == m.editField {
+			s.WriteString(headerStyle.Render(item.label))
+		}
+	}
 	s.WriteString("\n\n")
-	config := boxStyle.Render(fmt.Sprintf(
-		"📂 Source folder: %s\n"+
-			"📁 Destination folder: %s\n"+
+	s.WriteString(boxStyle.Render(fmt.Sprintf("%s\n\nInput: %s", hints[m.editField], m.input.view(true))))
+	s.WriteString("\n\n")
+	if m.editField == "template" {
+		s.WriteString(m.viewTemplateSamples())
+		s.WriteString("\n\n")
+	}
+	s.WriteString(infoStyle.Render(textFieldHelp))
+	return s.String()
+}
+func (m model) confirmLines() []string {
+	summary := fmt.Sprintf(
+		"📂 Source folders: %s\n"+
+			"📁 Destination folders: %s\n"+
+			"💽 Destination mode: %s\n"+
+			"🔀 Routing: %s\n"+
+			"🔁 Only new or changed: %s\n"+
 			"📄 Formats: %s\n"+
 			"🔍 Recursive: %s\n"+
-			"📋 Copy mode: flat (all files in one folder)\n"+
+			"📏 Size: %s – %s\n"+
+			"📅 Date (%s): %s – %s\n"+
+			"📸 Photo date from EXIF: %s\n"+
+			"🔗 Symbolic links: %s\n"+
+			"📎 Sidecar files: %s\n"+
+			"🧬 Detect type by content: %s\n"+
+			"🏷️  Fix extension on copy: %s\n"+
+			"🙈 Ignore files: %s • Skip junk: %s\n"+
+			"🚫 Skip folders: %s\n"+
+			"🏷️  File names: %s\n"+
+			"✏️  Invalid characters replaced with: %s\n"+
+			"📋 Copy mode: %s\n"+
+			"🗂️  Subfolders: %s\n"+
 			"🧪 Dry run mode: %s",
-		m.config.SourceDir,
-		m.config.DestDir,
+		strings.Join(m.config.sourceRoots(), "\n                   "),
+		strings.Join(m.config.DestDirs, "\n                        "),
+		getDestModeDisplay(m.config.DestMode),
+		m.routingSummary(),
+		m.config.syncDisplay(),
 		strings.Join(m.config.Extensions, ", "),
 		getBoolDisplay(m.config.Recursive),
+		getSizeDisplay(m.config.MinSize),
+		getSizeDisplay(m.config.MaxSize),
+		m.config.dateFilterSource(),
+		getDateDisplay(m.config.ModifiedAfter),
+		getDateDisplay(m.config.ModifiedBefore),
+		getBoolDisplay(m.config.UseExifDate),
+		getSymlinkDisplay(m.config.symlinkPolicy()),
+		getBoolDisplay(m.config.Sidecars),
+		getBoolDisplay(m.config.DetectType),
+		getBoolDisplay(m.config.DetectType && m.config.FixExtension),
+		getBoolDisplay(m.config.UseIgnoreFiles),
+		getBoolDisplay(m.config.SkipJunk),
+		getListDisplay(m.config.SkipDirs),
+		getTemplateDisplay(m.config.NameTemplate),
+		m.config.nameReplacement(),
+		m.config.copyModeDisplay(),
+		getBucketDisplay(m.config),
 		getBoolDisplay(m.config.DryRun),
-	))
-	s.WriteString(config)
+	)
+	return strings.Split(summary, "\n")
+}
+func (m model) viewConfirm() string {
+	var s strings.Builder
+	s.WriteString(headerStyle.Render("🚀 Operation confirmation"))
+	s.WriteString("\n\n")
+	lines := m.confirmLines()
+	start := min(m.confirmScroll, max(len(lines)-confirmPageSize, 0))
+	end := min(start+confirmPageSize, len(lines))
+	page := lines[start:end]
+	if start > 0 {
+		page = append([]string{infoStyle.Render(fmt.Sprintf("↑ %d more", start))}, page...)
+	}
+	if end < len(lines) {
+		page = append(page, infoStyle.Render(fmt.Sprintf("↓ %d more (↑/↓ to scroll)", len(lines)-end)))
+	}
+	s.WriteString(boxStyle.Render(strings.Join(page, "\n")))
 	s.WriteString("\n\n")
 	buttons := []string{"✅ Start", "❌ Cancel"}
 	for i, button := range buttons {
@@ -896,11 +1513,37 @@ func (m model) viewComplete() string {
 	var s strings.Builder
 	s.WriteString(headerStyle.Render("✅ Operation completed"))
 	s.WriteString("\n\n")
+	var bySource strings.Builder
+	if roots := m.config.sourceRoots(); len(roots) > 1 {
+		for _, root := range roots {
+			bySource.WriteString(fmt.Sprintf("  📂 %s: %d\n", getDisplayPath(root), m.stats.byRoot[root]))
+		}
+	}
+	dests := m.allDests()
+	for _, dest := range dests {
+		if len(dests) > 1 || m.stats.failedByDest[dest] > 0 {
+			bySource.WriteString(fmt.Sprintf("  📁 %s: %d copied, %d failed\n", getDisplayPath(dest), m.stats.byDest[dest], m.stats.failedByDest[dest]))
+		}
+	}
+	bySource.WriteString(reportList("✏️  Renamed for the destination filesystem", m.stats.renames))
+	bySource.WriteString(reportList("❌ Failed", m.stats.failures))
+	bySource.WriteString(reportList("⏭️  Not copied (links and special files)", m.stats.notCopied))
+	if m.stats.sidecars > 0 || m.stats.sidecarFailed > 0 {
+		bySource.WriteString(fmt.Sprintf("  📎 Sidecars copied: %d, failed: %d\n", m.stats.sidecars, m.stats.sidecarFailed))
+	}
+	if m.stats.skipped > 0 {
+		bySource.WriteString(fmt.Sprintf("  🔁 Unchanged, skipped: %d\n", m.stats.skipped))
+	}
+	if m.stats.manifestErr != nil {
+		bySource.WriteString(errorStyle.Render(fmt.Sprintf("  Manifest or undo record not written: %v", m.stats.manifestErr)) + "\n")
+	}
 	result := boxStyle.Render(fmt.Sprintf(
 		"Files copied: %d of %d\n"+
+			"%s"+
 			"Operation completed successfully!",
 		m.copiedFiles,
 		m.totalFiles,
+		bySource.String(),
 	))
 	s.WriteString(result)
 	s.WriteString("\n\n")
@@ -933,7 +1576,6 @@ func getAvailableDrives() []shortcut {
 	return drives
 }
 
-
 func getShortcuts() []shortcut {
 	homeDir, _ := os.UserHomeDir()
 	currentDir, _ := os.Getwd()
@@ -979,6 +1621,18 @@ func getDisplayPath(path string) string {
 	}
 	return path
 }
+func errOrEmpty(err error) string {
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return ""
+}
+func getListDisplay(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
 func getBoolDisplay(value bool) string {
 	if value {
 		return "✅ Yes"
@@ -990,11 +1644,37 @@ func main() {
 		runTestMode()
 		return
 	}
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		os.Exit(runRestore(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "undo" {
+		os.Exit(runUndo())
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatch(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && (os.Args[1] == "--profile" || strings.HasPrefix(os.Args[1], "--profile=")) {
+		name, ok := strings.CutPrefix(os.Args[1], "--profile=")
+		if !ok {
+			if len(os.Args) < 3 {
+				fmt.Println("Usage: ficout --profile <name>")
+				os.Exit(2)
+			}
+			name = os.Args[2]
+		}
+		os.Exit(runProfile(name))
+	}
+	p := tea.NewProgram(initialModel().restoreSession(), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
+	if m, ok := final.(model); ok {
+		if err := m.saveSession(); err != nil {
+			fmt.Printf("Warning: could not save session: %v\n", err)
+		}
+	}
 }
 func runTestMode() {
 	fmt.Println("🧪 ficout test mode")
@@ -1003,7 +1683,7 @@ func runTestMode() {
 	fmt.Printf("✅ Model initialized: %s\n", m.currentPath)
 	shortcuts := getShortcuts()
 	fmt.Printf("✅ Found %d directory shortcuts\n", len(shortcuts))
-	m.config.SourceDir = m.currentPath
+	m.config.SourceDirs = []string{m.currentPath}
 	m.config.Extensions = []string{".go", ".md"}
 	files, err := m.scanFiles()
 	if err != nil {
@@ -1012,7 +1692,7 @@ func runTestMode() {
 		fmt.Printf("✅ Found %d files with extensions %v\n", len(files), m.config.Extensions)
 		for i, file := range files {
 			if i < 3 {
-				fmt.Printf("   - %s\n", filepath.Base(file))
+				fmt.Printf("   - %s\n", filepath.Base(file.path))
 			}
 		}
 		if len(files) > 3 {
@@ -1023,8 +1703,8 @@ func runTestMode() {
 	fmt.Printf("✅ Found %d subdirectories\n", len(dirs))
 	if _, err := os.Stat("test_source"); err == nil {
 		fmt.Println("\n📁 Testing copy functionality...")
-		m.config.SourceDir = filepath.Join(m.currentPath, "test_source")
-		m.config.DestDir = filepath.Join(m.currentPath, "test_dest")
+		m.config.SourceDirs = []string{filepath.Join(m.currentPath, "test_source")}
+		m.config.DestDirs = []string{filepath.Join(m.currentPath, "test_dest")}
 		m.config.Extensions = []string{".txt", ".md"}
 		m.config.DryRun = false
 		files, err := m.scanFiles()
@@ -1035,21 +1715,21 @@ func runTestMode() {
 			if len(files) > 0 {
 				fmt.Printf("✅ Files found for copying:\n")
 				for _, file := range files {
-					fmt.Printf("   - %s (from %s)\n", filepath.Base(file), filepath.Dir(file))
+					fmt.Printf("   - %s (from %s)\n", filepath.Base(file.path), filepath.Dir(file.path))
 				}
 				if !m.config.DryRun {
 					fmt.Println("📋 Copying files in flat structure...")
 					copied := 0
 					for _, file := range files {
-						err := m.copyFile(file)
+						err := copyError(m.copyFile(file))
 						if err == nil {
 							copied++
-							fmt.Printf("   ✅ %s\n", filepath.Base(file))
+							fmt.Printf("   ✅ %s\n", filepath.Base(file.path))
 						} else {
-							fmt.Printf("   ❌ %s: %v\n", filepath.Base(file), err)
+							fmt.Printf("   ❌ %s: %v\n", filepath.Base(file.path), err)
 						}
 					}
-					fmt.Printf("📁 Copied %d files to %s\n", copied, m.config.DestDir)
+					fmt.Printf("📁 Copied %d files to %s\n", copied, strings.Join(m.config.DestDirs, ", "))
 				} else {
 					fmt.Println("🧪 Dry-run mode - files not copied")
 				}
@@ -1062,15 +1742,40 @@ func runTestMode() {
 		"jpg, png, .gif",
 		"  .doc,   .docx  , txt  ",
 		"mp4 avi .mkv",
+		"tar.gz, .NII.GZ, =Makefile",
 	}
 	for _, input := range testInputs {
 		parsed := m.parseExtensions(input)
 		fmt.Printf("   '%s' → %v\n", input, parsed)
 	}
+	fmt.Println("\n🔧 Testing content type detection...")
+	samples := []struct {
+		name   string
+		header []byte
+	}{
+		{"IMG_0001", []byte{0xFF, 0xD8, 0xFF, 0xE1}},
+		{"download(3)", []byte("%PDF-1.7")},
+		{"clip.bin", []byte("\x00\x00\x00\x18ftypisom")},
+		{"photo.jpg", []byte("\x00\x00\x00\x18ftypheic")},
+		{"notes.txt", []byte("BMW service log")},
+	}
+	for _, sample := range samples {
+		fmt.Printf("   '%s' → %q\n", sample.name, sniffType(sample.header))
+	}
+	fmt.Println("\n🔧 Testing size and date filters...")
+	for _, input := range []string{"500", "10MB", "2GiB", "1.5k"} {
+		size, err := parseSize(input)
+		fmt.Printf("   '%s' → %d bytes (%s) %v\n", input, size, formatSize(size), errOrEmpty(err))
+	}
+	now := time.Now()
+	for _, input := range []string{"2024-01-31", "7d", "3mo", "1y", "soon"} {
+		date, err := parseDateSpec(input, now)
+		fmt.Printf("   '%s' → %s %v\n", input, date.Format("2006-01-02 15:04"), errOrEmpty(err))
+	}
 	if _, err := os.Stat("test_source"); err == nil {
 		fmt.Println("\n📁 Test with custom extensions...")
-		m.config.SourceDir = filepath.Join(m.currentPath, "test_source")
-		m.config.DestDir = filepath.Join(m.currentPath, "test_dest")
+		m.config.SourceDirs = []string{filepath.Join(m.currentPath, "test_source")}
+		m.config.DestDirs = []string{filepath.Join(m.currentPath, "test_dest")}
 		m.config.Extensions = m.parseExtensions(".log, ini, csv")
 		files, err := m.scanFiles()
 		if err != nil {
@@ -1078,7 +1783,7 @@ func runTestMode() {
 		} else {
 			fmt.Printf("✅ Found %d files with custom extensions %v:\n", len(files), m.config.Extensions)
 			for _, file := range files {
-				fmt.Printf("   - %s\n", filepath.Base(file))
+				fmt.Printf("   - %s\n", filepath.Base(file.path))
 			}
 		}
 	}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const manifestPrefix = "ficout-manifest-"
+
+type manifestEntry struct {
+	Source string    `json:"source"`
+	Root   string    `json:"root"`
+	Rel    string    `json:"rel"`
+	Dest   string    `json:"dest"`
+	Name   string    `json:"name"`
+	Size   int64     `json:"size"`
+	Hash   string    `json:"hash"`
+	MTime  time.Time `json:"mtime"`
+}
+type manifest struct {
+	Created time.Time       `json:"created"`
+	Dests   []string        `json:"dests"`
+	Entries []manifestEntry `json:"entries"`
+}
+type copyJob struct {
+	m        model
+	volume   int
+	manifest manifest
+	indexes  map[string]*syncIndex
+	replaced map[string]createdFile
+}
+
+func (m model) newCopyJob() *copyJob {
+	m.chunks = map[string]*chunkState{}
+	m.planned = map[string]*plannedDir{}
+	return &copyJob{m: m, manifest: manifest{Created: time.Now()}, indexes: map[string]*syncIndex{}, replaced: map[string]createdFile{}}
+}
+func (j *copyJob) copy(file sourceFile) []copyResult {
+	var results []copyResult
+	_, routed := j.m.destsFor(file)
+	switch {
+	case j.m.config.Incremental:
+		results = j.copyIncremental(file)
+	case j.m.config.DestMode == destModeSpill && !routed:
+		results = j.spill(file)
+	default:
+		results = j.m.copyFile(file)
+	}
+	info, err := file.stat()
+	if err != nil {
+		return results
+	}
+	if j.m.config.Incremental {
+		j.recordSync(file, info, results)
+	}
+	for _, result := range results {
+		if result.err != nil || result.skipped {
+			continue
+		}
+		name, err := filepath.Rel(result.dest, result.path)
+		if err != nil {
+			name = filepath.Base(result.path)
+		}
+		j.manifest.Entries = append(j.manifest.Entries, manifestEntry{
+			Source: file.path,
+			Root:   file.root,
+			Rel:    file.rel,
+			Dest:   result.dest,
+			Name:   name,
+			Size:   info.Size(),
+			Hash:   result.hash,
+			MTime:  info.ModTime(),
+		})
+		for _, sidecar := range result.sidecars {
+			j.recordSidecar(file, result.dest, sidecar)
+		}
+	}
+	return results
+}
+func (j *copyJob) recordSidecar(file sourceFile, dest string, sidecar sidecarCopy) {
+	info, err := os.Stat(sidecar.source)
+	if err != nil {
+		return
+	}
+	hash, err := fileHash(sidecar.path)
+	if err != nil {
+		return
+	}
+	name, err := filepath.Rel(dest, sidecar.path)
+	if err != nil {
+		name = filepath.Base(sidecar.path)
+	}
+	j.manifest.Entries = append(j.manifest.Entries, manifestEntry{
+		Source: sidecar.source,
+		Root:   file.root,
+		Rel:    filepath.ToSlash(filepath.Join(filepath.Dir(file.rel), filepath.Base(sidecar.source))),
+		Dest:   dest,
+		Name:   name,
+		Size:   info.Size(),
+		Hash:   hash,
+		MTime:  info.ModTime(),
+	})
+}
+func (j *copyJob) finish() error {
+	var errs []error
+	if err := j.saveSyncIndexes(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(j.manifest.Entries) == 0 {
+		return errors.Join(errs...)
+	}
+	record := jobRecord{Kind: "copy", Created: j.manifest.Created}
+	for _, entry := range j.manifest.Entries {
+		created := j.replaced[filepath.Join(entry.Dest, entry.Name)]
+		created.Path, created.Hash = filepath.Join(entry.Dest, entry.Name), entry.Hash
+		if j.m.config.Incremental {
+			created.SyncIndex = syncIndexName(entry.Root, entry.Dest)
+			created.SyncRel = entry.Rel
+		}
+		record.Files = append(record.Files, created)
+	}
+	used := map[string]bool{}
+	j.manifest.Dests = nil
+	for _, entry := range j.manifest.Entries {
+		used[entry.Dest] = true
+	}
+	for _, dest := range j.m.allDests() {
+		if used[dest] {
+			j.manifest.Dests = append(j.manifest.Dests, dest)
+		}
+	}
+	data, err := json.MarshalIndent(j.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := manifestPrefix + j.manifest.Created.Format("20060102-150405") + ".json"
+	for _, dest := range j.manifest.Dests {
+		path := filepath.Join(dest, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			errs = append(errs, err)
+		} else {
+			record.Manifests = append(record.Manifests, path)
+		}
+	}
+	if err := saveJobRecord(record); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+func readManifest(path string) (manifest, error) {
+	var mf manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mf, err
+	}
+	if err := json.Unmarshal(data, &mf); err != nil {
+		return mf, fmt.Errorf("%s: %w", path, err)
+	}
+	return mf, nil
+}
+func (mf manifest) restoreTarget(entry manifestEntry, target string) string {
+	if target == "" {
+		return entry.Source
+	}
+	roots := map[string]bool{}
+	for _, e := range mf.Entries {
+		roots[e.Root] = true
+	}
+	if len(roots) > 1 {
+		return filepath.Join(target, filepath.Base(entry.Root), entry.Rel)
+	}
+	return filepath.Join(target, entry.Rel)
+}
+func checkManifestFile(path string, entry manifestEntry) (bool, error) {
+	if hash, ok := linkHash(path); ok {
+		return hash == entry.Hash, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() != entry.Size {
+		return false, nil
+	}
+	hash, err := fileHash(path)
+	return hash == entry.Hash, err
+}
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyPlainFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+func copyPlainFile(src, dst string) error {
+	if target, err := os.Readlink(src); err == nil {
+		return os.Symlink(target, dst)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
+func runRestore(args []string) int {
+	move := false
+	var positional []string
+	for _, arg := range args {
+		switch arg {
+		case "--move":
+			move = true
+		case "--copy":
+			move = false
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) < 1 || len(positional) > 2 {
+		fmt.Println("Usage: ficout restore [--copy|--move] <manifest> [target folder]")
+		return 2
+	}
+	manifestPath := positional[0]
+	target := ""
+	if len(positional) == 2 {
+		target = positional[1]
+	}
+	mf, err := readManifest(manifestPath)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	var order []string
+	bySource := map[string][]manifestEntry{}
+	for _, entry := range mf.Entries {
+		if _, seen := bySource[entry.Source]; !seen {
+			order = append(order, entry.Source)
+		}
+		bySource[entry.Source] = append(bySource[entry.Source], entry)
+	}
+	manifestDir := filepath.Dir(manifestPath)
+	record := jobRecord{Kind: "restore", Created: time.Now()}
+	restored, missing, modified, failed := 0, 0, 0, 0
+	for _, source := range order {
+		entries := bySource[source]
+		dst := mf.restoreTarget(entries[0], target)
+		var found, changed string
+		var entry manifestEntry
+		for _, e := range entries {
+			for _, candidate := range []string{filepath.Join(e.Dest, e.Name), filepath.Join(manifestDir, e.Name)} {
+				ok, err := checkManifestFile(candidate, e)
+				if err != nil {
+					continue
+				}
+				if !ok {
+					changed = candidate
+					continue
+				}
+				found, entry = candidate, e
+				break
+			}
+			if found != "" {
+				break
+			}
+		}
+		switch {
+		case found == "" && changed != "":
+			fmt.Printf("   ⚠️  modified: %s\n", changed)
+			modified++
+			continue
+		case found == "":
+			fmt.Printf("   ❓ missing: %s\n", entries[0].Name)
+			missing++
+			continue
+		}
+		if _, err := os.Stat(dst); err == nil {
+			if ok, _ := checkManifestFile(dst, entry); ok {
+				restored++
+				continue
+			}
+			fmt.Printf("   ❌ %s: already exists and differs\n", dst)
+			failed++
+			continue
+		}
+		err := os.MkdirAll(filepath.Dir(dst), 0755)
+		if err == nil && move {
+			err = moveFile(found, dst)
+		} else if err == nil {
+			err = copyPlainFile(found, dst)
+		}
+		if err != nil {
+			fmt.Printf("   ❌ %s: %v\n", dst, err)
+			failed++
+			continue
+		}
+		os.Chtimes(dst, entry.MTime, entry.MTime)
+		created := createdFile{Path: dst, Hash: entry.Hash}
+		if move {
+			created.Origin = found
+		}
+		record.Files = append(record.Files, created)
+		restored++
+	}
+	if err := saveJobRecord(record); err != nil {
+		fmt.Printf("⚠️  Could not record job for undo: %v\n", err)
+	}
+	verb := "Copied"
+	if move {
+		verb = "Moved"
+	}
+	fmt.Printf("📁 %s %d of %d files back into place\n", verb, restored, len(order))
+	if missing+modified+failed > 0 {
+		fmt.Printf("   ❓ %d missing, ⚠️  %d modified, ❌ %d failed\n", missing, modified, failed)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const presetsFileName = "presets.json"
+
+type extensionPreset struct {
+	Name       string   `json:"name"`
+	Icon       string   `json:"icon,omitempty"`
+	Extensions []string `json:"extensions"`
+}
+
+var defaultPresets = []extensionPreset{
+	{"Images", "🖼️ ", []string{".jpg", ".png", ".gif", ".bmp", ".webp", ".heic", ".tif", ".avif", ".svg"}},
+	{"RAW photos", "📷", []string{".dng", ".cr2", ".cr3", ".nef", ".nrw", ".arw", ".srf", ".sr2", ".orf", ".rw2", ".raf", ".pef", ".srw", ".3fr", ".erf", ".kdc", ".raw"}},
+	{"Documents", "📄", []string{".pdf", ".doc", ".docx", ".odt", ".rtf", ".txt", ".md", ".pages"}},
+	{"Spreadsheets", "📊", []string{".xls", ".xlsx", ".ods", ".csv", ".tsv", ".numbers"}},
+	{"Presentations", "📽️ ", []string{".ppt", ".pptx", ".odp", ".key"}},
+	{"Ebooks", "📚", []string{".epub", ".mobi", ".azw", ".azw3", ".fb2", ".djvu", ".cbz", ".cbr"}},
+	{"Video", "🎬", []string{".mp4", ".m4v", ".mov", ".avi", ".mkv", ".webm", ".wmv", ".flv", ".mpg", ".3gp", ".mts", ".m2ts"}},
+	{"Subtitles", "💬", []string{".srt", ".ass", ".ssa", ".vtt", ".sub", ".idx"}},
+	{"Audio", "🎵", []string{".mp3", ".wav", ".flac", ".m4a", ".aac", ".ogg", ".opus", ".wma", ".aif"}},
+	{"Archives", "📦", []string{".zip", ".rar", ".7z", ".tar", ".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst", ".gz", ".bz2", ".xz", ".zst"}},
+	{"Source code", "💻", []string{".go", ".py", ".js", ".ts", ".jsx", ".tsx", ".java", ".kt", ".c", ".h", ".cpp", ".hpp", ".cs", ".rs", ".rb", ".php", ".swift", ".sh", ".sql", ".html", ".css", ".json", ".yml", ".toml"}},
+}
+
+func loadPresets() ([]extensionPreset, error) {
+	var presets []extensionPreset
+	if err := loadJSON(presetsFileName, &presets); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return slices.Clone(defaultPresets), nil
+		}
+		return slices.Clone(defaultPresets), fmt.Errorf("presets: %w", err)
+	}
+	return presets, nil
+}
+func savePresets(presets []extensionPreset) error {
+	return saveJSON(presetsFileName, presets)
+}
+func (p extensionPreset) label() string {
+	icon := p.Icon
+	if icon == "" {
+		icon = "⭐"
+	}
+	return icon + " " + p.Name
+}
+func mergeExtensions(lists ...[]string) []string {
+	var merged []string
+	for _, list := range lists {
+		for _, ext := range list {
+			if !slices.Contains(merged, ext) {
+				merged = append(merged, ext)
+			}
+		}
+	}
+	return merged
+}
+func (m model) selectedPresetExtensions() []string {
+	var lists [][]string
+	for _, preset := range m.presets {
+		if m.presetSelected[preset.Name] {
+			lists = append(lists, preset.Extensions)
+		}
+	}
+	if len(lists) == 0 && m.cursor < len(m.presets) {
+		lists = append(lists, m.presets[m.cursor].Extensions)
+	}
+	return mergeExtensions(lists...)
+}
+func (m model) openPresetEditor(index int) (model, tea.Cmd) {
+	m.presetIndex = index
+	m.presetField = 0
+	m.nameInput = newTextField("")
+	m.input = newTextField(strings.Join(m.config.Extensions, ", "))
+	if index >= 0 {
+		m.nameInput = newTextField(m.presets[index].Name)
+		m.input = newTextField(strings.Join(m.presets[index].Extensions, ", "))
+	}
+	m.state = stateEditPreset
+	if len(m.config.SourceDirs) > 0 {
+		m.inventory = nil
+		return m, m.startInventory()
+	}
+	return m, nil
+}
+func (m model) updateEditPreset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "down":
+		m.presetField = 1 - m.presetField
+	case "tab":
+		suggestions := extensionSuggestions(m.input, m.inventory, 1)
+		if m.presetField == 1 && len(suggestions) > 0 {
+			m.input.completeToken(suggestions[0].ext)
+		} else {
+			m.presetField = 1 - m.presetField
+		}
+	case "esc":
+		m.message = ""
+		m.state = stateExtensions
+		if m.presetIndex >= 0 {
+			m.cursor = m.presetIndex
+		} else {
+			m.cursor = len(m.presets)
+		}
+	case "enter":
+		name := strings.TrimSpace(m.nameInput.String())
+		exts := m.parseExtensions(m.input.String())
+		if problems := validateExtensionInput(m.input.String()); len(problems) > 0 {
+			m.message = "Please fix: " + strings.Join(problems, "; ")
+			return m, nil
+		}
+		if name == "" || len(exts) == 0 {
+			m.message = "A preset needs a name and at least one extension"
+			return m, nil
+		}
+		for i, preset := range m.presets {
+			if i != m.presetIndex && strings.EqualFold(preset.Name, name) {
+				m.message = fmt.Sprintf("A preset named %q already exists", name)
+				return m, nil
+			}
+		}
+		presets := slices.Clone(m.presets)
+		if m.presetIndex >= 0 {
+			if old := presets[m.presetIndex].Name; old != name && m.presetSelected[old] {
+				delete(m.presetSelected, old)
+				m.presetSelected[name] = true
+			}
+			presets[m.presetIndex].Name = name
+			presets[m.presetIndex].Extensions = exts
+		} else {
+			presets = append(presets, extensionPreset{Name: name, Extensions: exts})
+			m.presetIndex = len(presets) - 1
+		}
+		if err := savePresets(presets); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		m.presets = presets
+		m.message = ""
+		m.state = stateExtensions
+		m.cursor = m.presetIndex
+	default:
+		if m.presetField == 0 {
+			m.nameInput.update(msg)
+		} else {
+			m.input.update(msg)
+		}
+	}
+	return m, nil
+}
+func (m model) deletePreset(index int) model {
+	presets := slices.Delete(slices.Clone(m.presets), index, index+1)
+	if err := savePresets(presets); err != nil {
+		m.message = err.Error()
+		return m
+	}
+	delete(m.presetSelected, m.presets[index].Name)
+	m.message = fmt.Sprintf("Deleted preset %q", m.presets[index].Name)
+	m.presets = presets
+	if m.cursor >= len(presets) && m.cursor > 0 {
+		m.cursor--
+	}
+	return m
+}
+func (m model) viewEditPreset() string {
+	var s strings.Builder
+	title := "➕ New preset"
+	if m.presetIndex >= 0 {
+		title = "✏️ Edit preset"
+	}
+	s.WriteString(headerStyle.Render(title))
+	s.WriteString("\n\n")
+	s.WriteString(boxStyle.Render(fmt.Sprintf(
+		"Name: %s\n\n"+
+			"Extensions separated by commas\n"+
+			"Example: .jpg, .png, heic, =Makefile\n\n"+
+			"Extensions: %s",
+		m.nameInput.view(m.presetField == 0),
+		m.input.view(m.presetField == 1),
+	)))
+	s.WriteString("\n")
+	for _, problem := range validateExtensionInput(m.input.String()) {
+		s.WriteString(errorStyle.Render("✗ " + problem))
+		s.WriteString("\n")
+	}
+	if m.presetField == 1 {
+		s.WriteString(m.viewExtensionSuggestions())
+	}
+	s.WriteString("\n")
+	s.WriteString(infoStyle.Render("↑/↓: switch field • Tab: complete • " + textFieldHelp))
+	return s.String()
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const profilesDirName = "profiles"
+
+func profileFileName(name string) string {
+	return filepath.Join(profilesDirName, name+".json")
+}
+func validateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+	if strings.ContainsAny(name, `/\:`) || name == "." || name == ".." {
+		return fmt.Errorf("profile name %q cannot contain / \\ or :", name)
+	}
+	return nil
+}
+func listProfiles() []string {
+	entries, err := os.ReadDir(filepath.Join(configDir(), profilesDirName))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+func loadProfile(name string) (Config, error) {
+	config := defaultConfig()
+	if err := loadJSON(profileFileName(name), &config); err != nil {
+		return config, fmt.Errorf("profile %q: %w", name, err)
+	}
+	return config, nil
+}
+func saveProfile(name string, config Config) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	return saveJSON(profileFileName(name), config)
+}
+func (m model) updateSaveProfile(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		name := strings.TrimSpace(m.input.String())
+		if err := saveProfile(name, m.config); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		m.profiles = listProfiles()
+		m.message = fmt.Sprintf("Saved profile %q", name)
+		m.state = stateMenu
+	case "esc":
+		m.message = ""
+		m.state = stateMenu
+	default:
+		m.input.update(msg)
+	}
+	return m, nil
+}
+func (m model) viewSaveProfile() string {
+	var s strings.Builder
+	s.WriteString(headerStyle.Render("💾 Save current settings as profile"))
+	s.WriteString("\n\n")
+	s.WriteString(boxStyle.Render(fmt.Sprintf(
+		"Profile name (an existing profile with this name is replaced)\n"+
+			"Example: camera-card, weekly-scans\n\n"+
+			"Name: %s",
+		m.input.view(true))))
+	s.WriteString("\n\n")
+	s.WriteString(infoStyle.Render(textFieldHelp))
+	s.WriteString("\n")
+	s.WriteString(infoStyle.Render("Stored in " + filepath.Join(configDir(), profilesDirName)))
+	return s.String()
+}
+func (m model) loadProfileIntoModel(name string) model {
+	config, err := loadProfile(name)
+	if err != nil {
+		m.message = err.Error()
+		return m
+	}
+	m.config = config
+	m.message = fmt.Sprintf("Loaded profile %q", name)
+	return m
+}
+func runProfile(name string) int {
+	config, err := loadProfile(name)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	if len(config.SourceDirs) == 0 || len(config.DestDirs) == 0 {
+		fmt.Printf("❌ Profile %q has no source or destination folder\n", name)
+		return 1
+	}
+	m := initialModel()
+	m.config = config
+	fmt.Printf("📋 Profile %q: %s → %s (%s)\n", name, strings.Join(config.SourceDirs, ", "), strings.Join(config.DestDirs, ", "), strings.Join(config.Extensions, ", "))
+	files, skipped, err := m.scanPlan(0)
+	if err != nil {
+		fmt.Printf("❌ Scanning error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("✅ Found %d files\n", len(files))
+	for _, item := range skipped {
+		fmt.Printf("   ⏭️  %s (not copied)\n", item)
+	}
+	if config.DryRun {
+		for _, file := range files {
+			fmt.Printf("   - %s\n", file.path)
+		}
+		fmt.Println("🧪 Dry-run mode - files not copied")
+		return 0
+	}
+	stats := newJobStats(len(files))
+	job := m.newCopyJob()
+	for _, file := range files {
+		results := job.copy(file)
+		stats.record(file, results)
+		for _, result := range results {
+			if result.err != nil {
+				fmt.Printf("   ❌ %s → %s: %v\n", file.path, result.dest, result.err)
+			} else if config.Verbose {
+				fmt.Printf("   ✅ %s → %s\n", file.path, result.path)
+			}
+			if result.renamedFrom != "" && result.err == nil {
+				fmt.Printf("   ✏️  %s → %s (renamed for destination filesystem)\n", result.renamedFrom, result.path)
+			}
+			if result.sidecarErr != nil {
+				fmt.Printf("   ⚠️  %s: %v\n", file.path, result.sidecarErr)
+			}
+			for _, sidecar := range result.sidecars {
+				if config.Verbose {
+					fmt.Printf("   📎 %s → %s\n", sidecar.source, sidecar.path)
+				}
+			}
+		}
+	}
+	if err := job.finish(); err != nil {
+		fmt.Printf("⚠️  Manifest or undo record not written: %v\n", err)
+	}
+	fmt.Printf("📁 Copied %d of %d files\n", stats.copied, len(files))
+	if stats.sidecars > 0 || stats.sidecarFailed > 0 {
+		fmt.Printf("   📎 Sidecars copied: %d, failed: %d\n", stats.sidecars, stats.sidecarFailed)
+	}
+	if stats.skipped > 0 {
+		fmt.Printf("   🔁 Unchanged, skipped: %d\n", stats.skipped)
+	}
+	if roots := config.sourceRoots(); len(roots) > 1 {
+		for _, root := range roots {
+			fmt.Printf("   📂 %s: %d\n", root, stats.byRoot[root])
+		}
+	}
+	failed := 0
+	for _, dest := range m.allDests() {
+		fmt.Printf("   📁 %s: %d copied, %d failed\n", dest, stats.byDest[dest], stats.failedByDest[dest])
+		failed += stats.failedByDest[dest]
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
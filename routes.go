@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const presetRoutePrefix = "@"
+
+type routeRule struct {
+	Match []string `json:"match"`
+	Dest  string   `json:"dest"`
+}
+
+func expandHome(dir string) string {
+	if rest, ok := strings.CutPrefix(dir, "~"); ok && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return dir
+}
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+func parseRoutes(input string) ([]routeRule, error) {
+	var routes []routeRule
+	for _, segment := range strings.Split(input, ";") {
+		if strings.TrimSpace(segment) == "" {
+			continue
+		}
+		segment = strings.ReplaceAll(segment, "→", "->")
+		patterns, dest, ok := strings.Cut(segment, "->")
+		dest = strings.TrimSpace(dest)
+		if !ok || dest == "" {
+			return nil, fmt.Errorf("%q: use patterns -> folder", strings.TrimSpace(segment))
+		}
+		route := routeRule{Dest: dest}
+		for _, p := range strings.Split(patterns, ",") {
+			p = strings.TrimSpace(p)
+			switch {
+			case p == "":
+				continue
+			case isGlobPattern(p):
+				if _, err := path.Match(p, ""); err != nil {
+					return nil, fmt.Errorf("%q: invalid pattern", p)
+				}
+			case !strings.HasPrefix(p, ".") && !strings.HasPrefix(p, presetRoutePrefix) && !strings.HasPrefix(p, exactNamePrefix):
+				p = "." + p
+			}
+			route.Match = append(route.Match, strings.ToLower(p))
+		}
+		if len(route.Match) == 0 {
+			return nil, fmt.Errorf("route to %s has no file types", dest)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+func formatRoutes(routes []routeRule) string {
+	var parts []string
+	for _, route := range routes {
+		parts = append(parts, strings.Join(route.Match, ", ")+" -> "+route.Dest)
+	}
+	return strings.Join(parts, "; ")
+}
+func (m model) routeMatches(route routeRule, name string) bool {
+	var extensions []string
+	for _, p := range route.Match {
+		if preset, ok := strings.CutPrefix(p, presetRoutePrefix); ok {
+			for _, candidate := range m.presets {
+				if strings.EqualFold(candidate.Name, preset) {
+					extensions = append(extensions, candidate.Extensions...)
+				}
+			}
+		} else if isGlobPattern(p) {
+			if ok, _ := path.Match(p, strings.ToLower(name)); ok {
+				return true
+			}
+		} else {
+			extensions = append(extensions, p)
+		}
+	}
+	em := Config{Extensions: extensions}.newExtensionMatcher()
+	return em.match(name, em.fileExtension(name))
+}
+func (m model) destsFor(file sourceFile) ([]string, bool) {
+	name := filepath.Base(file.path)
+	for _, route := range m.config.Routes {
+		if m.routeMatches(route, name) {
+			return []string{expandHome(route.Dest)}, true
+		}
+	}
+	return m.config.DestDirs, false
+}
+func (m model) allDests() []string {
+	dests := slices.Clone(m.config.DestDirs)
+	for _, route := range m.config.Routes {
+		if dest := expandHome(route.Dest); !slices.Contains(dests, dest) {
+			dests = append(dests, dest)
+		}
+	}
+	return dests
+}
+func getRoutesDisplay(routes []routeRule) string {
+	if len(routes) == 0 {
+		return "none"
+	}
+	if len(routes) == 1 {
+		return formatRoutes(routes)
+	}
+	return fmt.Sprintf("%d rules", len(routes))
+}
+func (m model) routingSummary() string {
+	if len(m.config.Routes) == 0 {
+		return "none"
+	}
+	var s strings.Builder
+	for _, route := range m.config.Routes {
+		s.WriteString(fmt.Sprintf("\n   %s → %s", strings.Join(route.Match, ", "), route.Dest))
+	}
+	s.WriteString(fmt.Sprintf("\n   everything else → %s", strings.Join(m.config.DestDirs, ", ")))
+	return s.String()
+}
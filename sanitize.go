@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	windowsReservedChars = `<>:"/\|?*`
+	defaultReplacement   = "_"
+)
+
+var windowsFilesystems = map[string]bool{
+	"vfat": true, "exfat": true, "ntfs": true, "smb": true, "smbfs": true, "windows": true,
+}
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+func (c Config) nameReplacement() string {
+	if c.NameReplacement == "" {
+		return defaultReplacement
+	}
+	return c.NameReplacement
+}
+func truncateKeepExt(name string, limit int) string {
+	if len(name) <= limit {
+		return name
+	}
+	stem, ext := splitExtension(name)
+	if len(ext) >= limit {
+		stem, ext = name, ""
+	}
+	cut := limit - len(ext)
+	for cut > 0 && !utf8.RuneStart(stem[cut]) {
+		cut--
+	}
+	return stem[:cut] + ext
+}
+func sanitizeName(name, fsType, replacement string) string {
+	if windowsFilesystems[fsType] {
+		var s strings.Builder
+		for _, r := range name {
+			if r < 0x20 || strings.ContainsRune(windowsReservedChars, r) {
+				s.WriteString(replacement)
+			} else {
+				s.WriteRune(r)
+			}
+		}
+		name = strings.TrimRight(s.String(), ". ")
+		if name == "" {
+			name = replacement
+		}
+		stem, _, _ := strings.Cut(name, ".")
+		if windowsReservedNames[strings.ToUpper(strings.TrimSpace(stem))] {
+			name = replacement + name
+		}
+	}
+	return truncateKeepExt(name, maxNameBytes)
+}
+func sanitizeRelPath(rel, fsType, replacement string) string {
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i, part := range parts {
+		parts[i] = sanitizeName(part, fsType, replacement)
+	}
+	return filepath.Join(parts...)
+}
+func reportList(title string, items []string) string {
+	const shown = 5
+	if len(items) == 0 {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("  %s: %d\n", title, len(items)))
+	for _, item := range items[:min(len(items), shown)] {
+		s.WriteString("     " + item + "\n")
+	}
+	if len(items) > shown {
+		s.WriteString(fmt.Sprintf("     … and %d more\n", len(items)-shown))
+	}
+	return s.String()
+}
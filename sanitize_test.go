@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateKeepExt(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{"short.jpg", 20, "short.jpg"},
+		{"exactly.jpg", 11, "exactly.jpg"},
+		{"longname.jpg", 8, "long.jpg"},
+		{"archive.tar.gz", 12, "archi.tar.gz"},
+		{"noext", 3, "noe"},
+		{"a.verylongextension", 5, "a.ver"},
+		{"ääää.jpg", 9, "ää.jpg"},
+		{"ääää.jpg", 7, "ä.jpg"},
+		{"ä.jpg", 4, "ä.j"},
+	}
+	for _, tt := range tests {
+		got := truncateKeepExt(tt.name, tt.limit)
+		if got != tt.want {
+			t.Errorf("truncateKeepExt(%q, %d) = %q, want %q", tt.name, tt.limit, got, tt.want)
+		}
+		if len(got) > tt.limit || !utf8.ValidString(got) {
+			t.Errorf("truncateKeepExt(%q, %d) = %q: too long or invalid UTF-8", tt.name, tt.limit, got)
+		}
+	}
+}
+func TestSanitizeName(t *testing.T) {
+	long := strings.Repeat("x", 300) + ".jpg"
+	tests := []struct {
+		name, fsType, replacement string
+		want                      string
+	}{
+		{`a:b?.jpg`, "ext4", "_", `a:b?.jpg`},
+		{`a:b?.jpg`, "vfat", "_", `a_b_.jpg`},
+		{`a<b>c|d*e".txt`, "ntfs", "-", `a-b-c-d-e-.txt`},
+		{"tab\there.txt", "exfat", "_", "tab_here.txt"},
+		{"trailing. . ", "vfat", "_", "trailing"},
+		{"...", "vfat", "_", "_"},
+		{"CON", "vfat", "_", "_CON"},
+		{"con.txt", "ntfs", "_", "_con.txt"},
+		{"Lpt1.tar.gz", "exfat", "_", "_Lpt1.tar.gz"},
+		{"console.txt", "vfat", "_", "console.txt"},
+		{"CON", "ext4", "_", "CON"},
+		{long, "ext4", "_", strings.Repeat("x", maxNameBytes-4) + ".jpg"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeName(tt.name, tt.fsType, tt.replacement); got != tt.want {
+			t.Errorf("sanitizeName(%q, %q) = %q, want %q", tt.name, tt.fsType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"slices"
+)
+
+const (
+	sessionFileName = "session.json"
+	maxRecentDirs   = 5
+)
+
+type session struct {
+	Config        Config   `json:"config"`
+	BrowsePath    string   `json:"browse_path,omitempty"`
+	RecentSources []string `json:"recent_sources,omitempty"`
+	RecentDests   []string `json:"recent_dests,omitempty"`
+}
+
+func (m model) restoreSession() model {
+	saved := session{Config: defaultConfig()}
+	if err := loadJSON(sessionFileName, &saved); err != nil {
+		return m
+	}
+	m.config = saved.Config
+	m.recentSources = saved.RecentSources
+	m.recentDests = saved.RecentDests
+	if info, err := os.Stat(saved.BrowsePath); err == nil && info.IsDir() {
+		m.currentPath = saved.BrowsePath
+	}
+	return m
+}
+func (m model) saveSession() error {
+	return saveJSON(sessionFileName, session{
+		Config:        m.config,
+		BrowsePath:    m.currentPath,
+		RecentSources: m.recentSources,
+		RecentDests:   m.recentDests,
+	})
+}
+func pushRecent(recent []string, path string) []string {
+	recent = slices.DeleteFunc(slices.Clone(recent), func(p string) bool { return p == path })
+	recent = append([]string{path}, recent...)
+	if len(recent) > maxRecentDirs {
+		recent = recent[:maxRecentDirs]
+	}
+	return recent
+}
+func (m model) shortcutsFor(recent []string) []shortcut {
+	var shortcuts []shortcut
+	for _, path := range recent {
+		shortcuts = append(shortcuts, shortcut{"🕘 " + getDisplayPath(path), path})
+	}
+	if len(shortcuts) > 0 {
+		shortcuts = append(shortcuts, shortcut{"", ""})
+	}
+	return append(shortcuts, getShortcuts()...)
+}
+func (m model) resetToDefaults() model {
+	wd, _ := os.Getwd()
+	m.config = defaultConfig()
+	m.currentPath = wd
+	m.presetSelected = map[string]bool{}
+	m.message = "Settings reset to defaults"
+	return m
+}
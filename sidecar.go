@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type sidecarCopy struct {
+	source string
+	path   string
+}
+
+func sidecarSuffix(primary, candidate string) (string, bool) {
+	stem := strings.TrimSuffix(primary, filepath.Ext(primary))
+	if len(candidate) <= len(stem) || !strings.EqualFold(candidate[:len(stem)], stem) {
+		return "", false
+	}
+	suffix := candidate[len(stem):]
+	ext := filepath.Ext(candidate)
+	if strings.EqualFold(suffix, ext) || strings.EqualFold(suffix, filepath.Ext(primary)+ext) {
+		return suffix, true
+	}
+	return "", false
+}
+func attachSidecars(files []sourceFile, allowed func(path string) bool) {
+	primary := map[string]bool{}
+	for _, file := range files {
+		primary[file.path] = true
+	}
+	claimed := map[string]bool{}
+	dirs := map[string][]os.DirEntry{}
+	for i := range files {
+		dir := filepath.Dir(files[i].path)
+		entries, ok := dirs[dir]
+		if !ok {
+			entries, _ = os.ReadDir(dir)
+			dirs[dir] = entries
+		}
+		name := filepath.Base(files[i].path)
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if !entry.Type().IsRegular() || primary[path] || claimed[path] || strings.HasPrefix(entry.Name(), ".") || !allowed(path) {
+				continue
+			}
+			if _, ok := sidecarSuffix(name, entry.Name()); ok {
+				files[i].sidecars = append(files[i].sidecars, path)
+				claimed[path] = true
+			}
+		}
+	}
+}
+func (file sourceFile) sidecarSuffixes() []string {
+	var suffixes []string
+	for _, sidecar := range file.sidecars {
+		if suffix, ok := sidecarSuffix(filepath.Base(file.path), filepath.Base(sidecar)); ok {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	return suffixes
+}
+func copySidecars(file sourceFile, primaryPath string) ([]sidecarCopy, error) {
+	var copied []sidecarCopy
+	var problems []string
+	stem := strings.TrimSuffix(primaryPath, filepath.Ext(primaryPath))
+	for i, suffix := range file.sidecarSuffixes() {
+		dst := stem + suffix
+		if err := copyPlainFile(file.sidecars[i], dst); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", filepath.Base(file.sidecars[i]), err))
+			continue
+		}
+		copied = append(copied, sidecarCopy{source: file.sidecars[i], path: dst})
+	}
+	if len(problems) > 0 {
+		return copied, fmt.Errorf("sidecars not copied: %s", strings.Join(problems, "; "))
+	}
+	return copied, nil
+}
+func pathFree(path string) bool {
+	_, err := os.Lstat(path)
+	return os.IsNotExist(err)
+}
+func sidecarPaths(primaryPath string, suffixes []string) []string {
+	stem := strings.TrimSuffix(primaryPath, filepath.Ext(primaryPath))
+	paths := make([]string, len(suffixes))
+	for i, suffix := range suffixes {
+		paths[i] = stem + suffix
+	}
+	return paths
+}
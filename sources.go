@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type sourceFile struct {
+	path     string
+	root     string
+	rel      string
+	index    int
+	sidecars []string
+	link     bool
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plainConfig Config
+	aux := struct {
+		*plainConfig
+		SourceDir string `json:"source_dir"`
+		DestDir   string `json:"dest_dir"`
+	}{plainConfig: (*plainConfig)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.SourceDir != "" && !slices.Contains(c.SourceDirs, aux.SourceDir) {
+		c.SourceDirs = append([]string{aux.SourceDir}, c.SourceDirs...)
+	}
+	if aux.DestDir != "" && !slices.Contains(c.DestDirs, aux.DestDir) {
+		c.DestDirs = append([]string{aux.DestDir}, c.DestDirs...)
+	}
+	return nil
+}
+func canonicalRoot(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	return filepath.Clean(dir)
+}
+func isWithin(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+func (c Config) sourceRoots() []string {
+	canonical := make([]string, len(c.SourceDirs))
+	for i, dir := range c.SourceDirs {
+		canonical[i] = canonicalRoot(dir)
+	}
+	var roots []string
+	for i, dir := range c.SourceDirs {
+		covered := false
+		for j, other := range canonical {
+			if i == j {
+				continue
+			}
+			if canonical[i] == other && j < i || canonical[i] != other && isWithin(canonical[i], other) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			roots = append(roots, dir)
+		}
+	}
+	return roots
+}
+func getSourcesDisplay(dirs []string) string {
+	switch len(dirs) {
+	case 0:
+		return "not selected"
+	case 1:
+		return getDisplayPath(dirs[0])
+	}
+	return fmt.Sprintf("%s (+%d more)", getDisplayPath(dirs[0]), len(dirs)-1)
+}
+func (m *model) addSourceDir(path string) {
+	m.recentSources = pushRecent(m.recentSources, path)
+	if slices.Contains(m.config.SourceDirs, path) {
+		m.message = fmt.Sprintf("%s is already a source folder", path)
+		return
+	}
+	m.config.SourceDirs = append(m.config.SourceDirs, path)
+	for _, root := range m.config.SourceDirs {
+		if root != path && isWithin(canonicalRoot(path), canonicalRoot(root)) {
+			m.message = fmt.Sprintf("%s is inside %s and will not be scanned twice", path, root)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const (
+	destModeMirror = "mirror"
+	destModeSpill  = "spill"
+	spillReserve   = 64 << 10
+)
+
+func (j *copyJob) spill(file sourceFile) []copyResult {
+	dests := j.m.config.DestDirs
+	info, err := file.stat()
+	if err != nil {
+		return []copyResult{{dest: dests[min(j.volume, len(dests)-1)], err: err}}
+	}
+	needed := uint64(info.Size()) + spillReserve
+	for _, sidecar := range file.sidecars {
+		if info, err := os.Stat(sidecar); err == nil {
+			needed += uint64(info.Size())
+		}
+	}
+	for volume := j.volume; volume < len(dests); volume++ {
+		dest := dests[volume]
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return []copyResult{{dest: dest, err: err}}
+		}
+		if free, ok := freeSpace(dest); ok && free < needed {
+			continue
+		}
+		results := j.m.copyTo(file, []string{dest})
+		if errors.Is(results[0].err, syscall.ENOSPC) {
+			continue
+		}
+		if results[0].err == nil {
+			j.volume = volume
+		}
+		return results
+	}
+	return []copyResult{{
+		dest: dests[len(dests)-1],
+		err:  fmt.Errorf("no destination volume has %s free", formatSize(int64(needed))),
+	}}
+}
+func getDestModeDisplay(mode string) string {
+	if mode == destModeSpill {
+		return "Spill over (fill in order)"
+	}
+	return "Mirror (copy to all)"
+}
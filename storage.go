@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ficout")
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "ficout")
+}
+func loadJSON(name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(configDir(), name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+func saveJSON(name string, v any) error {
+	dir := configDir()
+	if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	syncDirName     = "sync"
+	replacedDirName = ".ficout-replaced"
+)
+
+type syncEntry struct {
+	Size  int64     `json:"size"`
+	MTime time.Time `json:"mtime"`
+	Hash  string    `json:"hash,omitempty"`
+	Name  string    `json:"name"`
+}
+type syncIndex struct {
+	Source string               `json:"source"`
+	Dest   string               `json:"dest"`
+	Files  map[string]syncEntry `json:"files"`
+}
+
+func syncIndexName(source, dest string) string {
+	sum := sha256.Sum256([]byte(canonicalRoot(source) + "\x00" + canonicalRoot(dest)))
+	return filepath.Join(syncDirName, hex.EncodeToString(sum[:8])+".json")
+}
+func (j *copyJob) syncIndex(source, dest string) *syncIndex {
+	key := syncIndexName(source, dest)
+	if index, ok := j.indexes[key]; ok {
+		return index
+	}
+	index := &syncIndex{}
+	if err := loadJSON(key, index); err != nil || index.Files == nil {
+		index = &syncIndex{Files: map[string]syncEntry{}}
+	}
+	index.Source, index.Dest = source, dest
+	j.indexes[key] = index
+	return index
+}
+func (j *copyJob) unchanged(file sourceFile, dest string, info os.FileInfo) bool {
+	entry, ok := j.syncIndex(file.root, dest).Files[file.rel]
+	if !ok || entry.Size != info.Size() {
+		return false
+	}
+	if entry.MTime.Equal(info.ModTime()) && !j.m.config.SyncHash {
+		return true
+	}
+	if !j.m.config.SyncHash || entry.Hash == "" {
+		return false
+	}
+	hash, err := file.hash()
+	return err == nil && hash == entry.Hash
+}
+func (j *copyJob) setAsideOutdatedCopy(file sourceFile, dest string) createdFile {
+	entry, ok := j.syncIndex(file.root, dest).Files[file.rel]
+	if !ok || entry.Hash == "" {
+		return createdFile{}
+	}
+	path := filepath.Join(dest, entry.Name)
+	backup := filepath.Join(dest, replacedDirName, j.manifest.Created.Format("20060102-150405.000000"), entry.Name)
+	if !pathFree(backup) || os.MkdirAll(filepath.Dir(backup), 0755) != nil {
+		return createdFile{}
+	}
+	if same, err := sameContent(path, entry.Hash); err != nil || !same || os.Rename(path, backup) != nil {
+		return createdFile{}
+	}
+	j.m.releaseName(path)
+	return createdFile{Replaced: path, Backup: backup, Previous: &entry}
+}
+func (j *copyJob) settleOutdatedCopy(replaced createdFile, result copyResult) {
+	if result.err == nil {
+		j.replaced[result.path] = replaced
+	} else if os.Rename(replaced.Backup, replaced.Replaced) == nil {
+		j.m.reserveName(replaced.Replaced)
+		removeBackupDirs(replaced.Backup)
+	}
+}
+func (j *copyJob) copyIncremental(file sourceFile) []copyResult {
+	dests, routed := j.m.destsFor(file)
+	info, err := file.stat()
+	if err != nil {
+		return []copyResult{{dest: dests[0], err: err}}
+	}
+	var pending []string
+	var results []copyResult
+	for _, dest := range dests {
+		if j.unchanged(file, dest, info) {
+			results = append(results, copyResult{dest: dest, skipped: true})
+		} else {
+			pending = append(pending, dest)
+		}
+	}
+	if j.m.config.DestMode == destModeSpill && !routed {
+		if len(results) > 0 {
+			return results[:1]
+		}
+		return j.spill(file)
+	}
+	outdated := make([]createdFile, len(pending))
+	for i, dest := range pending {
+		outdated[i] = j.setAsideOutdatedCopy(file, dest)
+	}
+	if len(pending) > 0 {
+		copied := j.m.copyTo(file, pending)
+		for i, replaced := range outdated {
+			if replaced.Backup != "" {
+				j.settleOutdatedCopy(replaced, copied[i])
+			}
+		}
+		results = append(results, copied...)
+	}
+	return results
+}
+func (j *copyJob) recordSync(file sourceFile, info os.FileInfo, results []copyResult) {
+	for _, result := range results {
+		if result.err != nil || result.skipped {
+			continue
+		}
+		name, err := filepath.Rel(result.dest, result.path)
+		if err != nil {
+			continue
+		}
+		j.syncIndex(file.root, result.dest).Files[file.rel] = syncEntry{
+			Size:  info.Size(),
+			MTime: info.ModTime(),
+			Hash:  result.hash,
+			Name:  name,
+		}
+	}
+}
+func (j *copyJob) saveSyncIndexes() error {
+	for key, index := range j.indexes {
+		if err := saveJSON(key, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c Config) syncDisplay() string {
+	switch {
+	case !c.Incremental:
+		return getBoolDisplay(false)
+	case c.SyncHash:
+		return getBoolDisplay(true) + " (size, time and hash)"
+	}
+	return getBoolDisplay(true) + " (size and time)"
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const templateSampleCount = 5
+
+var templateTokens = map[string]string{
+	"name":    "",
+	"ext":     "",
+	"parent":  "",
+	"relpath": "",
+	"source":  "",
+	"mtime":   "2006-01-02",
+	"taken":   "2006-01-02",
+	"counter": "1",
+	"hash":    "8",
+}
+
+type templatePart struct {
+	literal string
+	token   string
+	arg     string
+}
+type templateFacts struct {
+	mtime time.Time
+	taken time.Time
+	hash  string
+}
+type templateSample struct {
+	file  sourceFile
+	base  string
+	facts templateFacts
+	err   error
+}
+type templateSamplesMsg struct {
+	samples []templateSample
+	err     error
+}
+
+func parseNameTemplate(template string) ([]templatePart, error) {
+	var parts []templatePart
+	rest := template
+	for rest != "" {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			parts = append(parts, templatePart{literal: rest})
+			break
+		}
+		if rest[start] == '}' {
+			return nil, fmt.Errorf("unexpected } in template")
+		}
+		if start > 0 {
+			parts = append(parts, templatePart{literal: rest[:start]})
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed { in template")
+		}
+		token, arg, _ := strings.Cut(rest[start+1:start+end], ":")
+		defaultArg, known := templateTokens[token]
+		if !known {
+			return nil, fmt.Errorf("unknown token {%s}", token)
+		}
+		if arg == "" {
+			arg = defaultArg
+		}
+		switch token {
+		case "counter":
+			if n, err := strconv.Atoi(arg); err != nil || n < 1 || n > 20 {
+				return nil, fmt.Errorf("{counter:%s}: width must be a number like 04", arg)
+			}
+		case "hash":
+			if n, err := strconv.Atoi(arg); err != nil || n < 1 || n > 64 {
+				return nil, fmt.Errorf("{hash:%s}: length must be between 1 and 64", arg)
+			}
+		}
+		parts = append(parts, templatePart{token: token, arg: arg})
+		rest = rest[start+end+1:]
+	}
+	return parts, nil
+}
+func splitExtension(base string) (string, string) {
+	ext := extensionMatcher{compound: compoundExtensions}.fileExtension(base)
+	if len(ext) > len(base) || !strings.EqualFold(base[len(base)-len(ext):], ext) {
+		ext = filepath.Ext(base)
+	}
+	return base[:len(base)-len(ext)], base[len(base)-len(ext):]
+}
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+func templateNeeds(parts []templatePart) (dates, hash bool) {
+	for _, part := range parts {
+		dates = dates || part.token == "mtime" || part.token == "taken"
+		hash = hash || part.token == "hash"
+	}
+	return dates, hash
+}
+func loadTemplateFacts(file sourceFile, dates, hash bool) (templateFacts, error) {
+	var facts templateFacts
+	if dates {
+		info, err := file.stat()
+		if err != nil {
+			return facts, err
+		}
+		facts.mtime = info.ModTime()
+		facts.taken = fileDate(file.path, info, true)
+	}
+	if hash {
+		sum, err := fileHash(file.path)
+		if err != nil {
+			return facts, err
+		}
+		facts.hash = sum
+	}
+	return facts, nil
+}
+func renderNameTemplate(parts []templatePart, file sourceFile, base, separator string, facts templateFacts) (string, error) {
+	name, ext := splitExtension(base)
+	var s strings.Builder
+	for _, part := range parts {
+		switch part.token {
+		case "":
+			s.WriteString(part.literal)
+		case "name":
+			s.WriteString(name)
+		case "ext":
+			s.WriteString(ext)
+		case "parent":
+			s.WriteString(filepath.Base(filepath.Dir(file.path)))
+		case "relpath":
+			if dir := filepath.ToSlash(filepath.Dir(file.rel)); dir != "." {
+				s.WriteString(strings.ReplaceAll(dir, "/", separator))
+			}
+		case "source":
+			s.WriteString(filepath.Base(canonicalRoot(file.root)))
+		case "mtime":
+			s.WriteString(facts.mtime.Format(part.arg))
+		case "taken":
+			s.WriteString(facts.taken.Format(part.arg))
+		case "counter":
+			width, _ := strconv.Atoi(part.arg)
+			s.WriteString(fmt.Sprintf("%0*d", width, file.index))
+		case "hash":
+			n, _ := strconv.Atoi(part.arg)
+			s.WriteString(facts.hash[:min(n, len(facts.hash))])
+		}
+	}
+	rendered := filepath.Clean(filepath.FromSlash(s.String()))
+	if rendered == "." || filepath.IsAbs(rendered) || !filepath.IsLocal(rendered) {
+		return "", fmt.Errorf("template gives invalid file name %q", s.String())
+	}
+	return rendered, nil
+}
+func (m model) loadTemplateSamples() tea.Cmd {
+	return func() tea.Msg {
+		files, _, err := m.scanPlan(templateSampleCount)
+		samples := make([]templateSample, len(files))
+		for i, file := range files {
+			samples[i] = templateSample{file: file, base: m.baseFileName(file)}
+			samples[i].facts, samples[i].err = loadTemplateFacts(file, true, true)
+		}
+		return templateSamplesMsg{samples: samples, err: err}
+	}
+}
+func (m model) viewTemplateSamples() string {
+	parts, err := parseNameTemplate(m.input.String())
+	if err != nil {
+		return errorStyle.Render("⚠️  " + err.Error())
+	}
+	if len(m.templateSamples) == 0 {
+		return infoStyle.Render("No matching files to preview yet")
+	}
+	var s strings.Builder
+	s.WriteString("Examples:\n")
+	for _, sample := range m.templateSamples {
+		name, err := renderNameTemplate(parts, sample.file, sample.base, m.config.pathSeparator(), sample.facts)
+		if sample.err != nil {
+			err = sample.err
+		}
+		if err != nil {
+			s.WriteString(fmt.Sprintf("  %s → %s\n", sample.file.rel, errorStyle.Render(err.Error())))
+		} else {
+			s.WriteString(fmt.Sprintf("  %s → %s\n", sample.file.rel, successStyle.Render(name)))
+		}
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+func getTemplateDisplay(template string) string {
+	if template == "" {
+		return "original name"
+	}
+	return template
+}
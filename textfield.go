@@ -0,0 +1,137 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const textFieldHelp = "←/→ Home/End: move • Ctrl+W: delete word • Enter: save • Esc: cancel"
+
+var cursorStyle = lipgloss.NewStyle().Reverse(true)
+
+type textField struct {
+	value []rune
+	pos   int
+}
+
+func newTextField(value string) textField {
+	runes := []rune(value)
+	return textField{value: runes, pos: len(runes)}
+}
+func (t textField) String() string {
+	return string(t.value)
+}
+func (t *textField) insert(s string) {
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	runes := []rune(s)
+	value := make([]rune, 0, len(t.value)+len(runes))
+	value = append(value, t.value[:t.pos]...)
+	value = append(value, runes...)
+	value = append(value, t.value[t.pos:]...)
+	t.value = value
+	t.pos += len(runes)
+}
+func (t *textField) deleteRange(from, to int) {
+	t.value = append(t.value[:from:from], t.value[to:]...)
+	t.pos = from
+}
+func (t textField) wordStart() int {
+	i := t.pos
+	for i > 0 && !isWordRune(t.value[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(t.value[i-1]) {
+		i--
+	}
+	return i
+}
+func (t textField) wordEnd() int {
+	i := t.pos
+	for i < len(t.value) && !isWordRune(t.value[i]) {
+		i++
+	}
+	for i < len(t.value) && isWordRune(t.value[i]) {
+		i++
+	}
+	return i
+}
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && r != ','
+}
+func (t textField) currentToken() (int, string) {
+	start := t.pos
+	for start > 0 && isWordRune(t.value[start-1]) {
+		start--
+	}
+	return start, string(t.value[start:t.pos])
+}
+func (t *textField) completeToken(replacement string) {
+	start, _ := t.currentToken()
+	t.deleteRange(start, t.pos)
+	t.insert(replacement)
+}
+func (t *textField) update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "left", "ctrl+b":
+		if t.pos > 0 {
+			t.pos--
+		}
+	case "right", "ctrl+f":
+		if t.pos < len(t.value) {
+			t.pos++
+		}
+	case "alt+left", "ctrl+left", "alt+b":
+		t.pos = t.wordStart()
+	case "alt+right", "ctrl+right", "alt+f":
+		t.pos = t.wordEnd()
+	case "home", "ctrl+a":
+		t.pos = 0
+	case "end", "ctrl+e":
+		t.pos = len(t.value)
+	case "backspace", "ctrl+h":
+		if t.pos > 0 {
+			t.deleteRange(t.pos-1, t.pos)
+		}
+	case "delete", "ctrl+d":
+		if t.pos < len(t.value) {
+			t.deleteRange(t.pos, t.pos+1)
+		}
+	case "ctrl+w", "alt+backspace":
+		t.deleteRange(t.wordStart(), t.pos)
+	case "alt+d", "alt+delete":
+		end := t.wordEnd()
+		t.deleteRange(t.pos, end)
+	case "ctrl+u":
+		t.deleteRange(0, t.pos)
+	case "ctrl+k":
+		t.deleteRange(t.pos, len(t.value))
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			t.insert(string(msg.Runes))
+			return true
+		}
+		return false
+	}
+	return true
+}
+func (t textField) view(focused bool) string {
+	if !focused {
+		return string(t.value)
+	}
+	before := string(t.value[:t.pos])
+	if t.pos == len(t.value) {
+		return before + cursorStyle.Render(" ")
+	}
+	return before + cursorStyle.Render(string(t.value[t.pos])) + string(t.value[t.pos+1:])
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const lastJobFileName = "last-job.json"
+
+type createdFile struct {
+	Path      string     `json:"path"`
+	Hash      string     `json:"hash"`
+	Origin    string     `json:"origin,omitempty"`
+	SyncIndex string     `json:"sync_index,omitempty"`
+	SyncRel   string     `json:"sync_rel,omitempty"`
+	Replaced  string     `json:"replaced,omitempty"`
+	Backup    string     `json:"backup,omitempty"`
+	Previous  *syncEntry `json:"previous,omitempty"`
+}
+type jobRecord struct {
+	Kind      string        `json:"kind"`
+	Created   time.Time     `json:"created"`
+	Files     []createdFile `json:"files"`
+	Manifests []string      `json:"manifests,omitempty"`
+}
+type undoReport struct {
+	removed  int
+	restored int
+	missing  int
+	problems []string
+}
+
+func saveJobRecord(record jobRecord) error {
+	if len(record.Files) == 0 {
+		return nil
+	}
+	var previous jobRecord
+	if err := loadJSON(lastJobFileName, &previous); err == nil && !previous.Created.Equal(record.Created) {
+		for _, file := range previous.Files {
+			if file.Backup != "" && os.Remove(file.Backup) == nil {
+				removeBackupDirs(file.Backup)
+			}
+		}
+	}
+	return saveJSON(lastJobFileName, record)
+}
+func removeBackupDirs(backup string) {
+	for dir := filepath.Dir(backup); os.Remove(dir) == nil && filepath.Base(dir) != replacedDirName; {
+		dir = filepath.Dir(dir)
+	}
+}
+func loadJobRecord() (jobRecord, error) {
+	var record jobRecord
+	if err := loadJSON(lastJobFileName, &record); err != nil {
+		if os.IsNotExist(err) {
+			return record, fmt.Errorf("no job to undo")
+		}
+		return record, err
+	}
+	if len(record.Files) == 0 {
+		return record, fmt.Errorf("no job to undo")
+	}
+	return record, nil
+}
+func sameContent(path, hash string) (bool, error) {
+	if sum, ok := linkHash(path); ok {
+		return sum == hash, nil
+	}
+	sum, err := fileHash(path)
+	return sum == hash, err
+}
+func undoLastJob() (undoReport, error) {
+	var report undoReport
+	record, err := loadJobRecord()
+	if err != nil {
+		return report, err
+	}
+	var remaining []createdFile
+	indexes := map[string]*syncIndex{}
+	for _, file := range slices.Backward(record.Files) {
+		same, err := sameContent(file.Path, file.Hash)
+		switch {
+		case os.IsNotExist(err):
+			report.missing++
+			continue
+		case err != nil:
+			report.problems = append(report.problems, fmt.Sprintf("%s: %v", file.Path, err))
+		case !same:
+			report.problems = append(report.problems, fmt.Sprintf("%s: modified since the job, kept", file.Path))
+		case file.Origin != "":
+			if _, err := os.Stat(file.Origin); err == nil {
+				report.problems = append(report.problems, fmt.Sprintf("%s: %s already exists", file.Path, file.Origin))
+			} else if err := os.MkdirAll(filepath.Dir(file.Origin), 0755); err != nil {
+				report.problems = append(report.problems, fmt.Sprintf("%s: %v", file.Path, err))
+			} else if err := moveFile(file.Path, file.Origin); err != nil {
+				report.problems = append(report.problems, fmt.Sprintf("%s: %v", file.Path, err))
+			} else {
+				report.restored++
+				continue
+			}
+		default:
+			if err := os.Remove(file.Path); err != nil {
+				report.problems = append(report.problems, fmt.Sprintf("%s: %v", file.Path, err))
+			} else {
+				forgetSyncEntry(indexes, file)
+				report.removed++
+				if err := restoreReplaced(indexes, file); err != nil {
+					report.problems = append(report.problems, fmt.Sprintf("%s: earlier copy not restored: %v", file.Replaced, err))
+				} else if file.Backup != "" {
+					report.restored++
+				}
+				continue
+			}
+		}
+		remaining = append(remaining, file)
+	}
+	for key, index := range indexes {
+		if err := saveJSON(key, index); err != nil {
+			report.problems = append(report.problems, err.Error())
+		}
+	}
+	if len(remaining) > 0 {
+		slices.Reverse(remaining)
+		record.Files = remaining
+		return report, saveJSON(lastJobFileName, record)
+	}
+	for _, path := range record.Manifests {
+		os.Remove(path)
+	}
+	err = os.Remove(filepath.Join(configDir(), lastJobFileName))
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return report, err
+}
+func restoreReplaced(indexes map[string]*syncIndex, file createdFile) error {
+	if file.Backup == "" {
+		return nil
+	}
+	if !pathFree(file.Replaced) {
+		return fmt.Errorf("%s already exists", file.Replaced)
+	}
+	if err := os.Rename(file.Backup, file.Replaced); err != nil {
+		return err
+	}
+	removeBackupDirs(file.Backup)
+	if index := indexes[file.SyncIndex]; index != nil && file.Previous != nil {
+		index.Files[file.SyncRel] = *file.Previous
+	}
+	return nil
+}
+func forgetSyncEntry(indexes map[string]*syncIndex, file createdFile) {
+	if file.SyncIndex == "" {
+		return
+	}
+	index, ok := indexes[file.SyncIndex]
+	if !ok {
+		index = &syncIndex{}
+		if err := loadJSON(file.SyncIndex, index); err != nil {
+			return
+		}
+		indexes[file.SyncIndex] = index
+	}
+	delete(index.Files, file.SyncRel)
+}
+func (r undoReport) String() string {
+	s := fmt.Sprintf("Undo: %d removed, %d moved back", r.removed, r.restored)
+	if r.missing > 0 {
+		s += fmt.Sprintf(", %d already gone", r.missing)
+	}
+	if len(r.problems) > 0 {
+		s += fmt.Sprintf(", %d kept", len(r.problems))
+	}
+	return s
+}
+func (m model) undoFromMenu() (tea.Model, tea.Cmd) {
+	if !m.pendingUndo {
+		record, err := loadJobRecord()
+		if err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		m.pendingUndo = true
+		m.message = fmt.Sprintf("Press Enter again to undo the %s job from %s (%d files)",
+			record.Kind, record.Created.Format("2006-01-02 15:04"), len(record.Files))
+		return m, nil
+	}
+	m.pendingUndo = false
+	report, err := undoLastJob()
+	m.message = report.String()
+	if len(report.problems) > 0 {
+		m.message += "\n" + report.problems[0]
+	}
+	if err != nil {
+		m.message += "\n" + err.Error()
+	}
+	return m, nil
+}
+func runUndo() int {
+	report, err := undoLastJob()
+	for _, problem := range report.problems {
+		fmt.Printf("   ⚠️  %s\n", problem)
+	}
+	if report.removed+report.restored+report.missing+len(report.problems) > 0 {
+		fmt.Printf("↩️  %s\n", report)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	if len(report.problems) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	symlinkSkip   = "skip"
+	symlinkCopy   = "copy"
+	symlinkFollow = "follow"
+)
+
+var errWalkStopped = errors.New("walk stopped")
+var symlinkPolicies = []string{symlinkSkip, symlinkCopy, symlinkFollow}
+
+type walker struct {
+	m       model
+	root    string
+	filter  scanFilter
+	ignore  *ignoreMatcher
+	visit   func(file sourceFile, d fs.DirEntry) bool
+	report  func(file sourceFile, d fs.DirEntry, reason string)
+	kept    func(path string)
+	visited map[string]bool
+	seen    map[string]bool
+	links   []func()
+	stopped bool
+}
+
+func (c Config) symlinkPolicy() string {
+	if c.Symlinks == "" {
+		return symlinkSkip
+	}
+	return c.Symlinks
+}
+func nextSymlinkPolicy(policy string) string {
+	for i, p := range symlinkPolicies {
+		if p == policy {
+			return symlinkPolicies[(i+1)%len(symlinkPolicies)]
+		}
+	}
+	return symlinkCopy
+}
+func getSymlinkDisplay(policy string) string {
+	switch policy {
+	case symlinkCopy:
+		return "Copy the link itself"
+	case symlinkFollow:
+		return "Follow (inside source folder only)"
+	}
+	return "Skip"
+}
+func fileKind(mode fs.FileMode) string {
+	switch {
+	case mode&fs.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	case mode&fs.ModeCharDevice != 0:
+		return "character device"
+	case mode&fs.ModeDevice != 0:
+		return "device"
+	}
+	return "special file"
+}
+func (m model) walkRoot(root string, filter scanFilter, visit func(file sourceFile, d fs.DirEntry) bool, report func(file sourceFile, d fs.DirEntry, reason string), kept func(path string)) error {
+	w := &walker{
+		m:       m,
+		root:    root,
+		filter:  filter,
+		ignore:  m.config.newIgnoreMatcher(),
+		visit:   visit,
+		report:  report,
+		kept:    kept,
+		visited: map[string]bool{},
+		seen:    map[string]bool{},
+	}
+	real := canonicalRoot(root)
+	w.visited[real] = true
+	w.walk(root, ".", real)
+	for len(w.links) > 0 && !w.stopped {
+		follow := w.links[0]
+		w.links = w.links[1:]
+		follow()
+	}
+	if w.stopped {
+		return errWalkStopped
+	}
+	return nil
+}
+func (w *walker) skipped(file sourceFile, d fs.DirEntry, reason string) {
+	if w.report != nil {
+		w.report(file, d, reason)
+	}
+}
+func (w *walker) walk(dir, rel, real string) {
+	w.ignore.load(dir, rel)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if w.stopped {
+			return
+		}
+		path := filepath.Join(dir, entry.Name())
+		childRel := entry.Name()
+		if rel != "." {
+			childRel = rel + "/" + entry.Name()
+		}
+		childReal := filepath.Join(real, entry.Name())
+		file := sourceFile{path: path, root: w.root, rel: childRel}
+		switch mode := entry.Type(); {
+		case mode.IsDir():
+			if !w.ignore.skip(childRel, true) && !w.visited[childReal] {
+				w.visited[childReal] = true
+				w.walk(path, childRel, childReal)
+			}
+		case mode&fs.ModeSymlink != 0:
+			w.symlink(file, entry)
+		case mode.IsRegular():
+			w.file(file, entry, childReal)
+		default:
+			if !w.ignore.skip(childRel, false) {
+				w.skipped(file, entry, fileKind(mode))
+			}
+		}
+	}
+}
+func (w *walker) file(file sourceFile, d fs.DirEntry, real string) {
+	if w.seen[real] || w.ignore.skip(file.rel, false) {
+		return
+	}
+	if w.kept != nil && d.Type().IsRegular() {
+		w.kept(file.path)
+	}
+	if !w.filter.match(file.path, d) {
+		return
+	}
+	w.seen[real] = true
+	w.stopped = !w.visit(file, d)
+}
+func (w *walker) symlink(file sourceFile, d fs.DirEntry) {
+	info, statErr := os.Stat(file.path)
+	isDir := statErr == nil && info.IsDir()
+	if w.ignore.skip(file.rel, isDir) {
+		return
+	}
+	switch w.m.config.symlinkPolicy() {
+	case symlinkCopy:
+		if isDir {
+			w.skipped(file, d, "symbolic link to a folder")
+			return
+		}
+		if !w.filter.match(file.path, d) {
+			return
+		}
+		file.link = true
+		w.stopped = !w.visit(file, d)
+		return
+	case symlinkSkip:
+		w.skipped(file, d, "symbolic link")
+		return
+	}
+	target, err := filepath.EvalSymlinks(file.path)
+	if err != nil || statErr != nil {
+		w.skipped(file, d, "broken symbolic link")
+		return
+	}
+	if !isWithin(target, canonicalRoot(w.root)) {
+		w.skipped(file, d, "symbolic link pointing outside the source folder")
+		return
+	}
+	w.links = append(w.links, func() { w.follow(file, d, target, info) })
+}
+func (w *walker) follow(file sourceFile, d fs.DirEntry, target string, info fs.FileInfo) {
+	isDir := info.IsDir()
+	switch {
+	case isDir && w.visited[target]:
+		w.skipped(file, d, "symbolic link loop or folder already scanned")
+	case isDir:
+		w.visited[target] = true
+		w.walk(file.path, file.rel, target)
+	case info.Mode().IsRegular():
+		w.file(file, fs.FileInfoToDirEntry(info), target)
+	default:
+		w.skipped(file, d, fileKind(info.Mode()))
+	}
+}
+func sourceLinkTarget(path string) (string, error) {
+	target, err := os.Readlink(path)
+	if err == nil && !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return target, err
+}
+func linkHash(path string) (string, bool) {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", false
+	}
+	return "symlink:" + target, true
+}
+func (f sourceFile) stat() (os.FileInfo, error) {
+	if f.link {
+		return os.Lstat(f.path)
+	}
+	return os.Stat(f.path)
+}
+func (f sourceFile) hash() (string, error) {
+	if f.link {
+		if target, err := sourceLinkTarget(f.path); err == nil {
+			return "symlink:" + target, nil
+		}
+	}
+	return fileHash(f.path)
+}
+func copyLink(file sourceFile, results []copyResult) {
+	target, readErr := sourceLinkTarget(file.path)
+	for i := range results {
+		if results[i].err != nil {
+			continue
+		}
+		err := readErr
+		if err == nil {
+			err = os.Symlink(target, results[i].path)
+		}
+		if results[i].err = err; err == nil {
+			results[i].hash = "symlink:" + target
+		}
+	}
+}
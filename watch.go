@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	watchSettle = 2 * time.Second
+	watchTick   = 500 * time.Millisecond
+)
+
+type watchEvent struct {
+	path     string
+	isDir    bool
+	overflow bool
+}
+type pendingFile struct {
+	root    string
+	size    int64
+	mtime   time.Time
+	changed time.Time
+}
+type seenFile struct {
+	size  int64
+	mtime time.Time
+}
+type watchSession struct {
+	m          model
+	watcher    *fileWatcher
+	job        *copyJob
+	stats      jobStats
+	roots      []string
+	dests      []string
+	ignores    map[string]*ignoreMatcher
+	filter     scanFilter
+	extensions extensionMatcher
+	pending    map[string]*pendingFile
+	seen       map[string]seenFile
+	folders    int
+	recorded   int
+}
+
+func watchLog(format string, args ...any) {
+	fmt.Printf("%s %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+}
+func (ws *watchSession) addTree(root, dir string, queueFiles bool) {
+	ignore := ws.ignores[root]
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && ignore.skip(rel, true) || ws.isDest(path) {
+				return fs.SkipDir
+			}
+			ignore.load(path, rel)
+			if err := ws.watcher.add(path); err != nil {
+				watchLog("⚠️  Cannot watch %s: %v", path, err)
+			} else {
+				ws.folders++
+			}
+			return nil
+		}
+		if queueFiles {
+			ws.pending[path] = &pendingFile{root: root, size: -1, changed: time.Now()}
+		} else if info, err := os.Lstat(path); err == nil {
+			ws.remember(path, info)
+		}
+		return nil
+	})
+}
+func (ws *watchSession) isDest(path string) bool {
+	path = canonicalRoot(path)
+	for _, dest := range ws.dests {
+		if isWithin(path, dest) {
+			return true
+		}
+	}
+	return false
+}
+func (ws *watchSession) rootFor(path string) string {
+	best := ""
+	for _, root := range ws.roots {
+		if isWithin(path, root) && len(root) > len(best) {
+			best = root
+		}
+	}
+	return best
+}
+func (ws *watchSession) remember(path string, info os.FileInfo) {
+	ws.seen[path] = seenFile{size: info.Size(), mtime: info.ModTime()}
+}
+func (ws *watchSession) rescan() {
+	watchLog("⚠️  Too many changes at once, rescanning the source folders")
+	for _, root := range ws.roots {
+		ws.ignores[root] = ws.m.config.newIgnoreMatcher()
+		ws.addTree(root, root, true)
+	}
+}
+func (ws *watchSession) handle(event watchEvent) {
+	if event.overflow {
+		ws.rescan()
+		return
+	}
+	root := ws.rootFor(event.path)
+	if root == "" || ws.isDest(event.path) {
+		return
+	}
+	if event.isDir {
+		rel, _ := filepath.Rel(root, event.path)
+		if !ws.ignores[root].skip(filepath.ToSlash(rel), true) {
+			ws.addTree(root, event.path, true)
+		}
+		return
+	}
+	if p, ok := ws.pending[event.path]; ok {
+		p.changed = time.Now()
+		return
+	}
+	ws.pending[event.path] = &pendingFile{root: root, size: -1, changed: time.Now()}
+}
+func (ws *watchSession) settle() {
+	for path, p := range ws.pending {
+		if time.Since(p.changed) < watchSettle {
+			continue
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			delete(ws.pending, path)
+			continue
+		}
+		if !info.Mode().IsRegular() && info.Mode()&fs.ModeSymlink == 0 {
+			delete(ws.pending, path)
+			if name := info.Name(); ws.extensions.match(name, ws.extensions.fileExtension(name)) {
+				rel, _ := filepath.Rel(p.root, path)
+				watchLog("⏭️  %s: %s, not copied", filepath.ToSlash(rel), fileKind(info.Mode()))
+			}
+			continue
+		}
+		if info.Size() != p.size || !info.ModTime().Equal(p.mtime) {
+			p.size, p.mtime, p.changed = info.Size(), info.ModTime(), time.Now()
+			continue
+		}
+		delete(ws.pending, path)
+		if seen, ok := ws.seen[path]; ok && seen.size == info.Size() && seen.mtime.Equal(info.ModTime()) {
+			continue
+		}
+		ws.remember(path, info)
+		ws.process(path, p.root, info)
+	}
+	if len(ws.job.manifest.Entries) != ws.recorded {
+		ws.recorded = len(ws.job.manifest.Entries)
+		if err := ws.job.finish(); err != nil {
+			watchLog("⚠️  Manifest or undo record not written: %v", err)
+		}
+	}
+}
+func (ws *watchSession) process(path, root string, info os.FileInfo) {
+	rel, _ := filepath.Rel(root, path)
+	rel = filepath.ToSlash(rel)
+	if ws.ignores[root].skip(rel, false) {
+		return
+	}
+	name := info.Name()
+	link := info.Mode()&fs.ModeSymlink != 0
+	if link {
+		if !ws.extensions.match(name, ws.extensions.fileExtension(name)) {
+			return
+		}
+		switch ws.m.config.symlinkPolicy() {
+		case symlinkSkip:
+			watchLog("⏭️  %s: symbolic link, not copied", rel)
+			return
+		case symlinkFollow:
+			target, err := filepath.EvalSymlinks(path)
+			if err != nil || !isWithin(target, canonicalRoot(root)) {
+				watchLog("⏭️  %s: broken or outside the source folder, not copied", rel)
+				return
+			}
+			if info, err = os.Stat(target); err != nil || !info.Mode().IsRegular() {
+				watchLog("⏭️  %s: link target is not a regular file, not copied", rel)
+				return
+			}
+			link = false
+		}
+	}
+	ext := ws.extensions.fileExtension(name)
+	if ws.m.config.DetectType && !link {
+		ext = realExtension(path, ext)
+	}
+	if !ws.extensions.match(name, ext) {
+		return
+	}
+	if !ws.filter.match(path, fs.FileInfoToDirEntry(info)) {
+		watchLog("⏭️  %s: outside size/date filter", rel)
+		return
+	}
+	ws.stats.total++
+	file := sourceFile{path: path, root: root, rel: rel, index: ws.stats.total, link: link}
+	if ws.m.config.Sidecars {
+		files := []sourceFile{file}
+		attachSidecars(files, func(path string) bool {
+			rel, _ := filepath.Rel(root, path)
+			return !ws.ignores[root].skip(filepath.ToSlash(rel), false)
+		})
+		file = files[0]
+	}
+	results := ws.job.copy(file)
+	ws.stats.record(file, results)
+	for _, result := range results {
+		switch {
+		case result.err != nil:
+			watchLog("❌ %s → %s: %v", rel, result.dest, result.err)
+		case result.skipped:
+			watchLog("🔁 %s: unchanged", rel)
+		default:
+			watchLog("✅ %s → %s", rel, result.path)
+		}
+	}
+}
+func runWatch(args []string) int {
+	config := defaultConfig()
+	source := "last session"
+	if len(args) > 0 {
+		name, ok := strings.CutPrefix(args[0], "--profile=")
+		if !ok && args[0] == "--profile" && len(args) > 1 {
+			name, ok = args[1], true
+		}
+		if !ok {
+			fmt.Println("Usage: ficout watch [--profile <name>]")
+			return 2
+		}
+		loaded, err := loadProfile(name)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		config, source = loaded, fmt.Sprintf("profile %q", name)
+	} else {
+		saved := session{Config: defaultConfig()}
+		if err := loadJSON(sessionFileName, &saved); err == nil {
+			config = saved.Config
+		}
+	}
+	if len(config.SourceDirs) == 0 || len(config.DestDirs) == 0 {
+		fmt.Printf("❌ The %s has no source or destination folder\n", source)
+		return 1
+	}
+	if config.DryRun {
+		fmt.Println("❌ Watch mode copies files as they appear; turn off dry run first")
+		return 1
+	}
+	filter, err := config.scanFilter(time.Now())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	watcher, err := newFileWatcher()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	defer watcher.close()
+	m := initialModel()
+	m.config = config
+	ws := &watchSession{
+		m:          m,
+		watcher:    watcher,
+		job:        m.newCopyJob(),
+		stats:      newJobStats(0),
+		ignores:    map[string]*ignoreMatcher{},
+		filter:     filter,
+		extensions: config.newExtensionMatcher(),
+		pending:    map[string]*pendingFile{},
+		seen:       map[string]seenFile{},
+	}
+	for _, dest := range m.allDests() {
+		ws.dests = append(ws.dests, canonicalRoot(dest))
+	}
+	for _, root := range config.sourceRoots() {
+		root = canonicalRoot(root)
+		ws.roots = append(ws.roots, root)
+		ws.ignores[root] = config.newIgnoreMatcher()
+		ws.addTree(root, root, false)
+	}
+	fmt.Printf("👀 Watching %d folders from %s → %s (%s)\n", ws.folders, source, strings.Join(config.DestDirs, ", "), strings.Join(config.Extensions, ", "))
+	fmt.Println("   New files are copied once they stop changing; press Ctrl+C to stop")
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(watchTick)
+	defer ticker.Stop()
+	status := 0
+loop:
+	for {
+		select {
+		case event, ok := <-watcher.events:
+			if !ok {
+				fmt.Printf("❌ %v\n", <-watcher.errs)
+				status = 1
+				break loop
+			}
+			ws.handle(event)
+		case now := <-ticker.C:
+			if filter, err := config.scanFilter(now); err == nil {
+				ws.filter = filter
+			}
+			ws.settle()
+		case <-signals:
+			break loop
+		}
+	}
+	if err := ws.job.finish(); err != nil {
+		fmt.Printf("⚠️  Manifest or undo record not written: %v\n", err)
+	}
+	fmt.Printf("\n📁 Copied %d of %d new files\n", ws.stats.copied, ws.stats.total)
+	return status
+}
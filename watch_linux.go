@@ -0,0 +1,87 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const watchMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_ATTRIB | unix.IN_DELETE_SELF
+
+type fileWatcher struct {
+	mu     sync.Mutex
+	fd     int
+	dirs   map[int]string
+	events chan watchEvent
+	errs   chan error
+}
+
+func newFileWatcher() (*fileWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+	w := &fileWatcher{
+		fd:     fd,
+		dirs:   map[int]string{},
+		events: make(chan watchEvent, 64),
+		errs:   make(chan error, 1),
+	}
+	go w.read()
+	return w, nil
+}
+func (w *fileWatcher) add(dir string) error {
+	wd, err := unix.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.dirs[wd] = dir
+	w.mu.Unlock()
+	return nil
+}
+func (w *fileWatcher) close() {
+	unix.Close(w.fd)
+}
+func (w *fileWatcher) read() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := unix.Read(w.fd, buf)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			w.errs <- err
+			close(w.events)
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(raw.Len)]
+			offset += unix.SizeofInotifyEvent + int(raw.Len)
+			if raw.Mask&unix.IN_Q_OVERFLOW != 0 {
+				w.events <- watchEvent{overflow: true}
+				continue
+			}
+			w.mu.Lock()
+			dir, ok := w.dirs[int(raw.Wd)]
+			w.mu.Unlock()
+			if !ok || raw.Mask&unix.IN_IGNORED != 0 {
+				continue
+			}
+			name := string(bytes.TrimRight(nameBytes, "\x00"))
+			if name == "" {
+				continue
+			}
+			w.events <- watchEvent{
+				path:  filepath.Join(dir, name),
+				isDir: raw.Mask&unix.IN_ISDIR != 0,
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+type fileWatcher struct {
+	events chan watchEvent
+	errs   chan error
+}
+
+func newFileWatcher() (*fileWatcher, error) {
+	return nil, errors.New("watch mode needs inotify and is only available on Linux")
+}
+func (w *fileWatcher) add(dir string) error {
+	return nil
+}
+func (w *fileWatcher) close() {}